@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"edna/internal/config"
 	"edna/internal/server"
 )
 
@@ -17,7 +20,20 @@ import (
 // @description Aplicação de Banco de Dados para Gerenciamento de Bares
 // @BasePath /api/v1
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
+// Encerra o servidor esperando as requisições em andamento até o timeout.
+// Passado o prazo, as conexões restantes são fechadas, cancelando o contexto das requisições.
+func shutdown(apiServer *http.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := apiServer.Shutdown(ctx)
+	if err != nil {
+		apiServer.Close()
+	}
+	return err
+}
+
+func gracefulShutdown(apiServer *http.Server, timeout time.Duration, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -28,11 +44,8 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 	log.Println("shutting down gracefully, press Ctrl+C again to force")
 	stop() // Allow Ctrl+C to force shutdown
 
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := apiServer.Shutdown(ctx); err != nil {
+	// The server has `timeout` to finish the requests it is currently handling
+	if err := shutdown(apiServer, timeout); err != nil {
 		log.Printf("Server forced to shutdown with error: %v", err)
 	}
 
@@ -43,17 +56,24 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 }
 
 func main() {
+	start := time.Now()
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		slog.Error("component initialized", "component", "config", "status", "invalid", "error", err.Error())
+		os.Exit(1)
+	}
+	slog.Info("component initialized", "component", "config", "status", "ok", "duration", time.Since(start), "env", cfg.Env)
 
-	server := server.NewServer()
+	server := server.NewServer(cfg)
 
 	// Create a done channel to signal when the shutdown is complete
 	done := make(chan bool, 1)
 
 	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(server, cfg.ShutdownTimeout, done)
 
-	log.Printf("Server listening at %s", server.Addr)
-	err := server.ListenAndServe()
+	slog.Info("server ready", "addr", server.Addr, "startup", time.Since(start))
+	err = server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		panic(fmt.Sprintf("http server error: %s", err))
 	}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestShutdownCancelsRequestsAtDeadline(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		close(cancelled)
+	})}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	go srv.Serve(l)
+	go http.Get("http://" + l.Addr().String())
+	<-started
+
+	timeout := 100 * time.Millisecond
+	begin := time.Now()
+	if err := shutdown(srv, timeout); err == nil {
+		t.Error("expected shutdown to report the deadline being exceeded")
+	}
+	if elapsed := time.Since(begin); elapsed < timeout {
+		t.Errorf("expected shutdown to wait for %s; returned after %s", timeout, elapsed)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected long-running request to be cancelled after the deadline")
+	}
+}
@@ -28,17 +28,829 @@ const docTemplate = `{
                 "responses": {
                     "404": {
                         "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/features": {
+            "get": {
+                "description": "Returns every optional feature and whether it is enabled in this deployment (FEATURE_\u003cNAME\u003e=on|off).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "List feature flags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003cADMIN_TOKEN\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "type": "boolean"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/maintenance": {
+            "get": {
+                "description": "Returns whether the maintenance mode is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Get maintenance mode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003cADMIN_TOKEN\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.maintenanceStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Enables or disables the maintenance mode at runtime. While enabled every route but health, admin and docs answers with 503.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Toggle maintenance mode",
+                "parameters": [
+                    {
+                        "description": "Maintenance status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.maintenanceStatus"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003cADMIN_TOKEN\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.maintenanceStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/routes": {
+            "get": {
+                "description": "Returns every registered method and path of the API. An empty method accepts any method.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "List API routes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003cADMIN_TOKEN\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/util.Route"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/aplica_oferta": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AplicaOferta"
+                ],
+                "summary": "List AplicaOferta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by id_oferta using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)",
+                        "name": "filter-id_oferta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by id_venda using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)",
+                        "name": "filter-id_venda",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by id_item_venda using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)",
+                        "name": "filter-id_item_venda",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields: id_oferta, id_venda, id_item_venda. Prefix with '-' for desc. Comma separated for multiple fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AplicaOferta"
                             }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AplicaOferta"
+                ],
+                "summary": "Create AplicaOferta",
+                "parameters": [
+                    {
+                        "description": "AplicaOferta payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AplicaOfertaResponse"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.AplicaOferta"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/aplica_oferta/count": {
+            "get": {
+                "description": "Returns how many rows match the filters accepted by GET /aplica_oferta, ignoring sort and pagination.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AplicaOferta"
+                ],
+                "summary": "Count AplicaOferta",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/aplica_oferta/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AplicaOferta"
+                ],
+                "summary": "Get AplicaOferta by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "AplicaOferta ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AplicaOferta"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AplicaOferta"
+                ],
+                "summary": "Update AplicaOferta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "AplicaOferta ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "AplicaOferta payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AplicaOfertaResponse"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AplicaOferta"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AplicaOferta"
+                ],
+                "summary": "Delete AplicaOferta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "AplicaOferta ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AplicaOferta"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cliente"
+                ],
+                "summary": "List Clients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by nome using operators: like, ilike, eq, ne. Format: operator.value (e.g. like.João)",
+                        "name": "filter-nome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by cnpj using operators: eq, ne, like, ilike. Format: operator.value (e.g. eq.123456789)",
+                        "name": "filter-cnpj",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields: nome, cnpj. Prefix with '-' for desc. Comma separated for multiple fields (e.g. -nome,cnpj)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Cliente"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cliente"
+                ],
+                "summary": "Create Cliente",
+                "parameters": [
+                    {
+                        "description": "Cliente payload",
+                        "name": "fornecedor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ClienteCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/count": {
+            "get": {
+                "description": "Returns how many rows match the filters accepted by GET /clientes, ignoring sort and pagination.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cliente"
+                ],
+                "summary": "Count Clientes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/saldo": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cliente"
+                ],
+                "summary": "List Clients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by nome using operators: like, ilike, eq, ne. Format: operator.value (e.g. like.João)",
+                        "name": "filter-nome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by cnpj using operators: eq, ne, like, ilike. Format: operator.value (e.g. eq.123456789)",
+                        "name": "filter-cnpj",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "format": "float32",
+                        "description": "Filter by saldo_devedor using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.100)",
+                        "name": "filter-saldo_devedor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields: nome, cnpj, saldo_devedor. Prefix with '-' for desc. Comma separated for multiple fields (e.g. -nome,cnpj)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Pagination limit (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ClienteWithSaldo"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cliente"
+                ],
+                "summary": "Get Cliente by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cliente"
+                ],
+                "summary": "Update Cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cliente payload",
+                        "name": "fornecedor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ClienteCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cliente"
+                ],
+                "summary": "Delete Cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be deleted in cascade, returning a model.DeletePreview",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/clientes": {
+        "/clientes/{id}/saldo": {
             "get": {
                 "produces": [
                     "application/json"
@@ -46,7 +858,47 @@ const docTemplate = `{
                 "tags": [
                     "Cliente"
                 ],
-                "summary": "List Clients",
+                "summary": "Fetch Client's Balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cliente ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ClienteWithSaldo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fornecedores": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fornecedor"
+                ],
+                "summary": "List Fornecedores",
                 "parameters": [
                     {
                         "type": "string",
@@ -85,7 +937,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Cliente"
+                                "$ref": "#/definitions/model.Fornecedor"
                             }
                         }
                     },
@@ -105,17 +957,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Cliente"
+                    "Fornecedor"
                 ],
-                "summary": "Create Cliente",
+                "summary": "Create Fornecedor",
                 "parameters": [
                     {
-                        "description": "Cliente payload",
+                        "description": "Fornecedor payload",
                         "name": "fornecedor",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.ClienteCreate"
+                            "$ref": "#/definitions/model.FornecedorCreate"
                         }
                     }
                 ],
@@ -123,7 +975,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Cliente"
+                            "$ref": "#/definitions/model.Fornecedor"
                         }
                     },
                     "400": {
@@ -141,62 +993,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/clientes/saldos": {
+        "/fornecedores/count": {
             "get": {
+                "description": "Returns how many rows match the filters accepted by GET /fornecedores, ignoring sort and pagination.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cliente"
-                ],
-                "summary": "List Clients",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter by nome using operators: like, ilike, eq, ne. Format: operator.value (e.g. like.João)",
-                        "name": "filter-nome",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by cnpj using operators: eq, ne, like, ilike. Format: operator.value (e.g. eq.123456789)",
-                        "name": "filter-cnpj",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "format": "float32",
-                        "description": "Filter by saldo_devedor using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.100)",
-                        "name": "filter-saldo_devedor",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort fields: nome, cnpj, saldo_devedor. Prefix with '-' for desc. Comma separated for multiple fields (e.g. -nome,cnpj)",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination offset (default 0)",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Pagination limit (default 10)",
-                        "name": "limit",
-                        "in": "query"
-                    }
+                    "Fornecedor"
                 ],
+                "summary": "Count Fornecedores",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.ClienteWithSaldo"
-                            }
+                            "$ref": "#/definitions/types.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
                     "500": {
@@ -208,19 +1025,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/clientes/{id}": {
+        "/fornecedores/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cliente"
+                    "Fornecedor"
                 ],
-                "summary": "Get Cliente by ID",
+                "summary": "Get Fornecedor by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cliente ID",
+                        "description": "Fornecedor ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -230,7 +1047,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Cliente"
+                            "$ref": "#/definitions/model.Fornecedor"
                         }
                     },
                     "400": {
@@ -261,24 +1078,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Cliente"
+                    "Fornecedor"
                 ],
-                "summary": "Update Cliente",
+                "summary": "Update Fornecedor",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cliente ID",
+                        "description": "Fornecedor ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Cliente payload",
+                        "description": "Fornecedor payload",
                         "name": "fornecedor",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.ClienteCreate"
+                            "$ref": "#/definitions/model.FornecedorCreate"
                         }
                     }
                 ],
@@ -286,7 +1103,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Cliente"
+                            "$ref": "#/definitions/model.Fornecedor"
                         }
                     },
                     "400": {
@@ -308,63 +1125,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Cliente"
+                    "Fornecedor"
                 ],
-                "summary": "Delete Cliente",
+                "summary": "Delete Fornecedor",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cliente ID",
+                        "description": "Fornecedor ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.Cliente"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/types.ErrorResponse"
-                        }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/types.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/clientes/{id}/saldo": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Cliente"
-                ],
-                "summary": "Fetch Client's Balance",
-                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Cliente ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Only report what would be deleted in cascade, returning a model.DeletePreview. Sold lotes block the delete and are listed in bloqueios",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ClienteWithSaldo"
+                            "$ref": "#/definitions/model.Fornecedor"
                         }
                     },
                     "400": {
@@ -382,15 +1165,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/fornecedores": {
+        "/funcionarios": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fornecedor"
+                    "Funcionario"
                 ],
-                "summary": "List Fornecedores",
+                "summary": "List Funcionarios",
                 "parameters": [
                     {
                         "type": "string",
@@ -400,13 +1183,13 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Filter by cnpj using operators: eq, ne, like, ilike. Format: operator.value (e.g. eq.123456789)",
-                        "name": "filter-cnpj",
+                        "description": "Filter by CPF using operators: eq, ne, like, ilike. Format: operator.value (e.g. eq.123456789)",
+                        "name": "filter-CPF",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort fields: nome, cnpj. Prefix with '-' for desc. Comma separated for multiple fields (e.g. -nome,cnpj)",
+                        "description": "Sort fields: nome, CPF. Prefix with '-' for desc. Comma separated for multiple fields (e.g. -nome,CPF)",
                         "name": "sort",
                         "in": "query"
                     },
@@ -429,7 +1212,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Fornecedor"
+                                "$ref": "#/definitions/model.Funcionario"
                             }
                         }
                     },
@@ -449,17 +1232,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Fornecedor"
+                    "Funcionario"
                 ],
-                "summary": "Create Fornecedor",
+                "summary": "Create Funcionario",
                 "parameters": [
                     {
-                        "description": "Fornecedor payload",
-                        "name": "fornecedor",
+                        "description": "Funcionario payload",
+                        "name": "funcionario",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.FornecedorCreate"
+                            "$ref": "#/definitions/model.FuncionarioCreate"
                         }
                     }
                 ],
@@ -467,7 +1250,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Fornecedor"
+                            "$ref": "#/definitions/model.Funcionario"
                         }
                     },
                     "400": {
@@ -485,19 +1268,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/fornecedores/{id}": {
+        "/funcionarios/count": {
             "get": {
+                "description": "Returns how many rows match the filters accepted by GET /funcionarios, ignoring sort and pagination.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fornecedor"
+                    "Funcionario"
                 ],
-                "summary": "Get Fornecedor by ID",
+                "summary": "Count Funcionarios",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/funcionarios/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Funcionario"
+                ],
+                "summary": "Get Funcionario by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Fornecedor ID",
+                        "description": "Funcionario ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -507,7 +1322,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Fornecedor"
+                            "$ref": "#/definitions/model.Funcionario"
                         }
                     },
                     "400": {
@@ -538,24 +1353,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Fornecedor"
+                    "Funcionario"
                 ],
-                "summary": "Update Fornecedor",
+                "summary": "Update Funcionario",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Fornecedor ID",
+                        "description": "Funcionario ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Fornecedor payload",
-                        "name": "fornecedor",
+                        "description": "Funcionario payload",
+                        "name": "funcionario",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.FornecedorCreate"
+                            "$ref": "#/definitions/model.FuncionarioCreate"
                         }
                     }
                 ],
@@ -563,7 +1378,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Fornecedor"
+                            "$ref": "#/definitions/model.Funcionario"
                         }
                     },
                     "400": {
@@ -585,13 +1400,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Fornecedor"
+                    "Funcionario"
                 ],
-                "summary": "Delete Fornecedor",
+                "summary": "Delete Funcionario",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Fornecedor ID",
+                        "description": "Funcionario ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -601,7 +1416,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Fornecedor"
+                            "$ref": "#/definitions/model.Funcionario"
                         }
                     },
                     "400": {
@@ -619,15 +1434,88 @@ const docTemplate = `{
                 }
             }
         },
-        "/funcionarios": {
+        "/health": {
             "get": {
+                "description": "Returns the health status of the application and dependencies. Responds 503 when the database is down.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Funcionario"
+                    "Server"
                 ],
-                "summary": "List Funcionarios",
+                "summary": "Check health of the system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/database.HealthStats"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/database.HealthStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/selftest": {
+            "get": {
+                "description": "Counts the produtos through the regular store, validating the path from handler to database, and reports the latency. Unlike /health it runs a real query.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Run a read-only self-test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003cADMIN_TOKEN\u003e",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/server.selfTestResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/server.selfTestResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/item_ofertas": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Item Oferta"
+                ],
+                "summary": "List Item Ofertas",
                 "parameters": [
                     {
                         "type": "string",
@@ -637,13 +1525,13 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Filter by CPF using operators: eq, ne, like, ilike. Format: operator.value (e.g. eq.123456789)",
-                        "name": "filter-CPF",
+                        "description": "Filter by cnpj using operators: eq, ne, like, ilike. Format: operator.value (e.g. eq.123456789)",
+                        "name": "filter-cnpj",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort fields: nome, CPF. Prefix with '-' for desc. Comma separated for multiple fields (e.g. -nome,CPF)",
+                        "description": "Sort fields: nome, cnpj. Prefix with '-' for desc. Comma separated for multiple fields (e.g. -nome,cnpj)",
                         "name": "sort",
                         "in": "query"
                     },
@@ -666,7 +1554,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Funcionario"
+                                "$ref": "#/definitions/model.ItemOferta"
                             }
                         }
                     },
@@ -679,32 +1567,18 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Funcionario"
-                ],
-                "summary": "Create Funcionario",
-                "parameters": [
-                    {
-                        "description": "Funcionario payload",
-                        "name": "funcionario",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.FuncionarioCreate"
-                        }
-                    }
+                    "Item Oferta"
                 ],
+                "summary": "Get ItemOferta by Item ID",
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Funcionario"
+                            "$ref": "#/definitions/model.ItemOferta"
                         }
                     },
                     "400": {
@@ -722,19 +1596,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/funcionarios/{id}": {
+        "/item_ofertas/count": {
             "get": {
+                "description": "Returns how many rows match the filters accepted by GET /item_ofertas, ignoring sort and pagination.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Funcionario"
+                    "Item Oferta"
                 ],
-                "summary": "Get Funcionario by ID",
+                "summary": "Count Item Ofertas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/item_ofertas/item/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Item Oferta"
+                ],
+                "summary": "Get ItemOferta by Item ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Funcionario ID",
+                        "description": "Item (Produto) ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -744,7 +1650,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Funcionario"
+                            "$ref": "#/definitions/model.ItemOferta"
                         }
                     },
                     "400": {
@@ -766,41 +1672,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/item_ofertas/oferta/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Funcionario"
+                    "Item Oferta"
                 ],
-                "summary": "Update Funcionario",
+                "summary": "Get ItemOferta by Oferta ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Funcionario ID",
+                        "description": "Oferta ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Funcionario payload",
-                        "name": "funcionario",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.FuncionarioCreate"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Funcionario"
+                            "$ref": "#/definitions/model.ItemOferta"
                         }
                     },
                     "400": {
@@ -809,27 +1705,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/item_ofertas/{id_produto}/{id_oferta}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Funcionario"
+                    "ItemOferta"
                 ],
-                "summary": "Delete Funcionario",
+                "summary": "Get ItemOferta by composed ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Funcionario ID",
-                        "name": "id",
+                        "description": "Produto ID",
+                        "name": "id_produto",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Oferta ID",
+                        "name": "id_oferta",
                         "in": "path",
                         "required": true
                     }
@@ -838,7 +1749,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Funcionario"
+                            "$ref": "#/definitions/model.ItemOferta"
                         }
                     },
                     "400": {
@@ -847,57 +1758,104 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Returns the health status of the application and dependencies.",
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server"
+                    "ItemOferta"
+                ],
+                "summary": "Update ItemOferta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Produto ID",
+                        "name": "id_produto",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Oferta ID",
+                        "name": "id_oferta",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ItemOferta payload",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ItemOfertaCreate"
+                        }
+                    }
                 ],
-                "summary": "Check health of the system",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ItemOferta"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/itemOfertas": {
-            "post": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Item Oferta"
                 ],
-                "summary": "Get ItemOferta by Item ID",
+                "summary": "Delete ItemOferta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Produto ID",
+                        "name": "id_produto",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Oferta ID",
+                        "name": "id_oferta",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/model.ItemOferta"
                         }
@@ -917,31 +1875,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/item_ofertas": {
+        "/item_venda": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Item Oferta"
+                    "ItemVenda"
                 ],
-                "summary": "List Item Ofertas",
+                "summary": "List ItemVenda",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Filter by nome using operators: like, ilike, eq, ne. Format: operator.value (e.g. like.João)",
-                        "name": "filter-nome",
+                        "type": "integer",
+                        "description": "Filter by id_venda using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)",
+                        "name": "filter-id_venda",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Filter by cnpj using operators: eq, ne, like, ilike. Format: operator.value (e.g. eq.123456789)",
-                        "name": "filter-cnpj",
+                        "type": "integer",
+                        "description": "Filter by id_produto using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)",
+                        "name": "filter-id_produto",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by quantidade using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)",
+                        "name": "filter-quantidade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Filter by valor_unitario using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)",
+                        "name": "filter-valor_unitario",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort fields: nome, cnpj. Prefix with '-' for desc. Comma separated for multiple fields (e.g. -nome,cnpj)",
+                        "description": "Sort fields: id_venda, id_produto, quantidade, valor_unitario. Prefix with '-' for desc. Comma separated for multiple fields",
                         "name": "sort",
                         "in": "query"
                     },
@@ -964,7 +1934,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.ItemOferta"
+                                "$ref": "#/definitions/model.ItemVenda"
                             }
                         }
                     },
@@ -975,31 +1945,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/item_ofertas/item/{id}": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Item Oferta"
+                    "ItemVenda"
                 ],
-                "summary": "Get ItemOferta by Item ID",
+                "summary": "Create ItemVenda",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Item (Produto) ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ItemVenda payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ItemVendaCreate"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.ItemOferta"
+                            "$ref": "#/definitions/model.ItemVenda"
                         }
                     },
                     "400": {
@@ -1008,14 +1981,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/types.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -1023,29 +1990,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/item_ofertas/oferta/{id}": {
+        "/item_venda/count": {
             "get": {
+                "description": "Returns how many rows match the filters accepted by GET /item_venda, ignoring sort and pagination.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Item Oferta"
-                ],
-                "summary": "Get ItemOferta by Oferta ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Oferta ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "ItemVenda"
                 ],
+                "summary": "Count ItemVenda",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ItemOferta"
+                            "$ref": "#/definitions/types.CountResponse"
                         }
                     },
                     "400": {
@@ -1054,12 +2013,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/types.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1069,27 +2022,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/item_ofertas/{id_produto}/{id_oferta}": {
+        "/item_venda/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ItemOferta"
+                    "ItemVenda"
                 ],
-                "summary": "Get ItemOferta by composed ID",
+                "summary": "Get ItemVenda by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Produto ID",
-                        "name": "id_produto",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Oferta ID",
-                        "name": "id_oferta",
+                        "description": "ItemVenda ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -1098,7 +2044,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ItemOferta"
+                            "$ref": "#/definitions/model.ItemVenda"
                         }
                     },
                     "400": {
@@ -1121,35 +2067,40 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Item Oferta"
+                    "ItemVenda"
                 ],
-                "summary": "Delete ItemOferta",
+                "summary": "Update ItemVenda",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Produto ID",
-                        "name": "id_produto",
+                        "description": "ItemVenda ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Oferta ID",
-                        "name": "id_oferta",
-                        "in": "path",
-                        "required": true
+                        "description": "ItemVenda payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ItemVendaCreate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ItemOferta"
+                            "$ref": "#/definitions/model.ItemVenda"
                         }
                     },
                     "400": {
@@ -1158,6 +2109,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -1165,43 +2122,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/item_ofertas/{id}": {
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ItemOferta"
+                    "ItemVenda"
                 ],
-                "summary": "Update ItemOferta",
+                "summary": "Delete ItemVenda",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ItemOferta ID",
+                        "description": "ItemVenda ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "ItemOferta payload",
-                        "name": "item",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.ItemOfertaCreate"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ItemOferta"
+                            "$ref": "#/definitions/model.ItemVenda"
                         }
                     },
                     "400": {
@@ -1210,6 +2153,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -1322,6 +2271,38 @@ const docTemplate = `{
                 }
             }
         },
+        "/lotes/count": {
+            "get": {
+                "description": "Returns how many rows match the filters accepted by GET /lotes, ignoring sort and pagination.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lote"
+                ],
+                "summary": "Count Lotes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/lotes/produtos/{id}": {
             "get": {
                 "produces": [
@@ -1509,6 +2490,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be deleted, returning a model.DeletePreview. Sold items block the delete and are listed in bloqueios",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1636,6 +2623,38 @@ const docTemplate = `{
                 }
             }
         },
+        "/ofertas/count": {
+            "get": {
+                "description": "Returns how many rows match the filters accepted by GET /ofertas, ignoring sort and pagination.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Oferta"
+                ],
+                "summary": "Count Ofertas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/ofertas/{id}": {
             "get": {
                 "produces": [
@@ -1746,6 +2765,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be deleted in cascade, returning a model.DeletePreview",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1876,6 +2901,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1985,8 +3016,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Comercial"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -2031,6 +3068,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2081,6 +3124,50 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/count": {
+            "get": {
+                "description": "Returns how many rows match the filters accepted by GET /produtos, ignoring sort and pagination.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Produtos"
+                ],
+                "summary": "Count Produtos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2156,7 +3243,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos/{id}": {
+        "/produtos/quantidade/{id}": {
             "get": {
                 "produces": [
                     "application/json"
@@ -2164,7 +3251,7 @@ const docTemplate = `{
                 "tags": [
                     "Produtos"
                 ],
-                "summary": "Get Produto by ID",
+                "summary": "Get Produto Quantidade",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2178,7 +3265,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Produto"
+                            "$ref": "#/definitions/model.ProdutoWithQnt"
                         }
                     },
                     "400": {
@@ -2187,6 +3274,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2194,18 +3287,75 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/produtos/stream": {
+            "get": {
+                "description": "Exports every product as newline-delimited JSON (one object per line), without buffering the whole result.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Produtos"
+                ],
+                "summary": "Stream Produtos (all types)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by nome. Format: \u003cop\u003e.\u003cvalue\u003e. Ops: like, ilike, eq, ne",
+                        "name": "filter-nome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by categoria. Format: \u003cop\u003e.\u003cvalue\u003e. Ops: like, ilike, eq, ne",
+                        "name": "filter-categoria",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by marca. Format: \u003cop\u003e.\u003cvalue\u003e. Ops: like, ilike, eq, ne",
+                        "name": "filter-marca",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by attribute. Allowed: nome, categoria, marca. Prefix '-' for desc. Comma separated",
+                        "name": "sort",
+                        "in": "query"
+                    }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UnionProduto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Produtos"
                 ],
-                "summary": "Update Produto",
+                "summary": "Get Produto by ID",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2213,15 +3363,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Product payload",
-                        "name": "produto",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.ProdutoCreate"
-                        }
                     }
                 ],
                 "responses": {
@@ -2237,6 +3378,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2245,11 +3392,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Produtos"
                 ],
-                "summary": "Delete Produto",
+                "summary": "Update Produto",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2257,13 +3410,22 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Product payload",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ProdutoCreate"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.Produto"
                         }
                     },
                     "400": {
@@ -2272,6 +3434,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2279,17 +3453,12 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/produtos/{id}/quantidades": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "tags": [
                     "Produtos"
                 ],
-                "summary": "Get Produto Quantidade",
+                "summary": "Delete Produto",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2297,13 +3466,25 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be deleted in cascade, returning a model.DeletePreview. Sold lotes and ofertas containing the produto block the delete and are listed in bloqueios",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ProdutoWithQnt"
+                            "$ref": "#/definitions/model.DeletePreview"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -2312,6 +3493,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2442,6 +3635,41 @@ const docTemplate = `{
                 }
             }
         },
+        "/schemas/{entity}": {
+            "get": {
+                "description": "Returns the JSON Schema of the body accepted when creating the entity, generated from the same rules used by the server validation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Get the JSON Schema of a create request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity name (fornecedor, cliente, funcionario, lote, produto, produto_comercial, oferta, venda, item_venda, item_oferta)",
+                        "name": "entity",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.JSONSchema"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/vendas": {
             "get": {
                 "produces": [
@@ -2539,7 +3767,39 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Venda"
+                            "$ref": "#/definitions/model.Venda"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vendas/count": {
+            "get": {
+                "description": "Returns how many rows match the filters accepted by GET /vendas, ignoring sort and pagination.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Venda"
+                ],
+                "summary": "Count Vendas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CountResponse"
                         }
                     },
                     "400": {
@@ -2548,8 +3808,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -2667,6 +3927,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be deleted in cascade, returning a model.DeletePreview",
+                        "name": "dry_run",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2690,9 +3956,114 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/{collection}/validate": {
+            "post": {
+                "description": "Runs the same validation as the create route of the entity and reports every problem found. Nothing is created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Server"
+                ],
+                "summary": "Validate a create request without persisting it",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ValidationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "database.HealthStats": {
+            "type": "object",
+            "properties": {
+                "breaker": {
+                    "description": "State of the circuit breaker in front of the queries (closed, open or half_open)",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "idle": {
+                    "type": "string"
+                },
+                "in_use": {
+                    "type": "string"
+                },
+                "max_idle_closed": {
+                    "type": "string"
+                },
+                "max_lifetime_closed": {
+                    "type": "string"
+                },
+                "max_open": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "open_connections": {
+                    "type": "string"
+                },
+                "saturation": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "wait_count": {
+                    "type": "string"
+                },
+                "wait_duration": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AplicaOferta": {
+            "type": "object",
+            "properties": {
+                "id_aplica_oferta": {
+                    "type": "integer"
+                },
+                "id_item_venda": {
+                    "type": "integer"
+                },
+                "id_oferta": {
+                    "type": "integer"
+                },
+                "id_venda": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AplicaOfertaResponse": {
+            "type": "object",
+            "properties": {
+                "id_item_venda": {
+                    "type": "integer"
+                },
+                "id_oferta": {
+                    "type": "integer"
+                },
+                "id_venda": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.Cliente": {
             "type": "object",
             "properties": {
@@ -2700,7 +4071,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "data_nascimento": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "id": {
                     "type": "integer"
@@ -2712,16 +4083,25 @@ const docTemplate = `{
         },
         "model.ClienteCreate": {
             "type": "object",
+            "required": [
+                "nome"
+            ],
             "properties": {
                 "cpf": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 11
                 },
                 "data_nascimento": {
                     "description": "Espera-se \"YYYY-MM-DD\" ou formato RFC3339",
-                    "type": "string"
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Date"
+                        }
+                    ]
                 },
                 "nome": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 }
             }
         },
@@ -2732,7 +4112,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "data_nascimento": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "id": {
                     "type": "integer"
@@ -2767,6 +4147,9 @@ const docTemplate = `{
         },
         "model.ComercialCreate": {
             "type": "object",
+            "required": [
+                "nome"
+            ],
             "properties": {
                 "categoria": {
                     "type": "string"
@@ -2775,13 +4158,63 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "nome": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "preco_venda": {
                     "type": "number"
                 }
             }
         },
+        "model.Date": {
+            "type": "object",
+            "properties": {
+                "time.Time": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeletePreview": {
+            "type": "object",
+            "properties": {
+                "bloqueios": {
+                    "description": "Linhas que referenciam o registro com ON DELETE RESTRICT, por tabela. Se houver\nalguma o DELETE real falha.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "cascata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "entidade": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Expediente": {
+            "type": "string",
+            "enum": [
+                "manha",
+                "tarde",
+                "noite",
+                "madrugada"
+            ],
+            "x-enum-varnames": [
+                "ExpedienteManha",
+                "ExpedienteTarde",
+                "ExpedienteNoite",
+                "ExpedienteMadrugada"
+            ]
+        },
         "model.FolhaPagamentoMensal": {
             "type": "object",
             "properties": {
@@ -2827,12 +4260,17 @@ const docTemplate = `{
         },
         "model.FornecedorCreate": {
             "type": "object",
+            "required": [
+                "nome"
+            ],
             "properties": {
                 "cnpj": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 14
                 },
                 "nome": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 }
             }
         },
@@ -2846,7 +4284,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "expediente": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Expediente"
                 },
                 "id": {
                     "type": "integer"
@@ -2858,30 +4296,40 @@ const docTemplate = `{
                     "type": "number"
                 },
                 "tipo": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.TipoFuncionario"
                 }
             }
         },
         "model.FuncionarioCreate": {
             "type": "object",
+            "required": [
+                "CPF",
+                "data_contratacao",
+                "expediente",
+                "nome",
+                "tipo"
+            ],
             "properties": {
                 "CPF": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 11
                 },
                 "data_contratacao": {
                     "type": "string"
                 },
                 "expediente": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Expediente"
                 },
                 "nome": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "salario": {
-                    "type": "number"
+                    "type": "number",
+                    "minimum": 0
                 },
                 "tipo": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.TipoFuncionario"
                 }
             }
         },
@@ -2898,7 +4346,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "expediente": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Expediente"
                 },
                 "id_funcionario": {
                     "type": "integer"
@@ -2913,7 +4361,7 @@ const docTemplate = `{
                     "type": "number"
                 },
                 "tipo": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.TipoFuncionario"
                 }
             }
         },
@@ -2945,11 +4393,53 @@ const docTemplate = `{
                 }
             }
         },
+        "model.ItemVenda": {
+            "type": "object",
+            "properties": {
+                "id_item_venda": {
+                    "type": "integer"
+                },
+                "id_lote": {
+                    "type": "integer"
+                },
+                "id_venda": {
+                    "type": "integer"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "valor_unitario": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ItemVendaCreate": {
+            "type": "object",
+            "required": [
+                "id_lote",
+                "id_venda"
+            ],
+            "properties": {
+                "id_lote": {
+                    "type": "integer"
+                },
+                "id_venda": {
+                    "type": "integer"
+                },
+                "quantidade": {
+                    "type": "integer"
+                },
+                "valor_unitario": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
         "model.Lote": {
             "type": "object",
             "properties": {
                 "data_fornecimento": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "estragados": {
                     "type": "integer"
@@ -2970,18 +4460,24 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "validade": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 }
             }
         },
         "model.LoteCreate": {
             "type": "object",
+            "required": [
+                "data_fornecimento",
+                "id_fornecedor",
+                "id_produto"
+            ],
             "properties": {
                 "data_fornecimento": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "estragados": {
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 0
                 },
                 "id_fornecedor": {
                     "type": "integer"
@@ -2996,7 +4492,7 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "validade": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 }
             }
         },
@@ -3004,13 +4500,13 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "data_criacao": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "data_fim": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "data_inicio": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "id_oferta": {
                     "type": "integer"
@@ -3028,21 +4524,28 @@ const docTemplate = `{
         },
         "model.OfertaCreate": {
             "type": "object",
+            "required": [
+                "nome"
+            ],
             "properties": {
                 "data_fim": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "data_inicio": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.Date"
                 },
                 "nome": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 },
                 "percentual_desconto": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
                 },
                 "valor_fixo": {
-                    "type": "number"
+                    "type": "number",
+                    "minimum": 0
                 }
             }
         },
@@ -3065,6 +4568,9 @@ const docTemplate = `{
         },
         "model.ProdutoCreate": {
             "type": "object",
+            "required": [
+                "nome"
+            ],
             "properties": {
                 "categoria": {
                     "type": "string"
@@ -3073,7 +4579,8 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "nome": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
                 }
             }
         },
@@ -3180,6 +4687,23 @@ const docTemplate = `{
                 }
             }
         },
+        "model.TipoFuncionario": {
+            "type": "string",
+            "enum": [
+                "garcom",
+                "seguranca",
+                "caixa",
+                "faxineiro",
+                "balconista"
+            ],
+            "x-enum-varnames": [
+                "TipoGarcom",
+                "TipoSeguranca",
+                "TipoCaixa",
+                "TipoFaxineiro",
+                "TipoBalconista"
+            ]
+        },
         "model.UnionProduto": {
             "type": "object",
             "properties": {
@@ -3225,6 +4749,10 @@ const docTemplate = `{
         },
         "model.VendaCreate": {
             "type": "object",
+            "required": [
+                "id_cliente",
+                "id_funcionario"
+            ],
             "properties": {
                 "data_hora_pagamento": {
                     "type": "string"
@@ -3243,6 +4771,34 @@ const docTemplate = `{
                 }
             }
         },
+        "server.maintenanceStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "server.selfTestResult": {
+            "type": "object",
+            "properties": {
+                "check": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "services_lote.GastoMensal": {
             "type": "object",
             "properties": {
@@ -3257,11 +4813,105 @@ const docTemplate = `{
                 }
             }
         },
+        "types.CountResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.ErrorResponse": {
             "type": "object",
             "properties": {
                 "detail": {
                     "type": "string"
+                },
+                "error": {
+                    "description": "Código do erro derivado do status HTTP, ex: \"not_found\"",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "Momento do erro em RFC 3339 (UTC)",
+                    "type": "string"
+                }
+            }
+        },
+        "util.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "util.JSONSchema": {
+            "type": "object",
+            "properties": {
+                "$schema": {
+                    "type": "string"
+                },
+                "exclusiveMinimum": {
+                    "type": "number"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/util.JSONSchema"
+                    }
+                },
+                "required": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {}
+            }
+        },
+        "util.Route": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "util.ValidationResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.FieldError"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
                 }
             }
         }
@@ -0,0 +1,298 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+	// Embute a base de fusos horários, a imagem do container pode não ter /usr/share/zoneinfo
+	_ "time/tzdata"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// Config reúne todas as configurações da aplicação lidas das variáveis de ambiente
+type Config struct {
+	Env  string
+	Port int
+	// Fuso usado para interpretar datas YYYY-MM-DD e decidir qual é o dia de hoje
+	Timezone *time.Location
+
+	// Tempo máximo para as requisições em andamento terminarem no desligamento
+	ShutdownTimeout time.Duration
+	// Inicia o servidor em modo de manutenção
+	MaintenanceMode bool
+	// Forma canônica das rotas: com barra final (true) ou sem (false)
+	PreferTrailingSlash bool
+	// Requisições por minuto, por cliente, nas rotas de relatório (0 desabilita)
+	RelatorioRateLimit int
+	// Proxies (IPs ou CIDRs) cujo header X-Real-IP identifica o cliente (vazio usa sempre o endereço da conexão)
+	TrustedProxies []netip.Prefix
+	// Máximo de linhas de uma listagem sem `limit` (0 desabilita)
+	MaxUnboundedRows int
+	// Novas tentativas das consultas de leitura após falha de serialização ou deadlock (0 desabilita)
+	ReadRetries int
+	// Fração (0 a 1) das requisições bem-sucedidas registradas no log, erros são sempre registrados
+	LogSampleRate float64
+	// Segundos que o navegador pode guardar a resposta do preflight CORS (0 desabilita o cache)
+	CORSMaxAge int
+	// Token exigido no header `Authorization: Bearer` pelas rotas /admin (vazio desabilita as rotas)
+	AdminToken string
+	// Valor do header Content-Security-Policy nas respostas da API ("off" omite o header)
+	ContentSecurityPolicy string
+	// Funcionalidades opcionais (FEATURE_<NOME>)
+	Features Features
+
+	Database DatabaseConfig
+}
+
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	Name     string
+	Username string
+	Password string
+	Schema   string
+	SSLMode  string
+	// Máximo de conexões abertas com o banco (0 não limita)
+	MaxOpenConns int
+	// Espera por uma conexão livre antes de responder 503
+	PoolWait time.Duration
+	// Fração do pool em uso (0 a 1) a partir da qual o banco é reportado como degraded (0 desabilita)
+	SaturationThreshold float64
+	// Falhas de conexão seguidas que abrem o circuit breaker do banco (0 desabilita)
+	BreakerThreshold int
+	// Tempo em que o circuit breaker aberto recusa as consultas antes de tentar de novo
+	BreakerCooldown time.Duration
+}
+
+const (
+	defaultPort               = 8080
+	defaultShutdownTimeout    = 5 * time.Second
+	defaultRelatorioRateLimit = 10
+	defaultMaxUnboundedRows   = 1000
+	defaultCORSMaxAge         = 600
+	defaultLogSampleRate      = 1.0
+	defaultReadRetries        = 2
+	defaultSaturation         = 0.8
+	defaultPoolWait           = 500 * time.Millisecond
+	defaultBreakerThreshold   = 5
+	defaultBreakerCooldown    = 30 * time.Second
+	// A API só responde JSON, nada deve ser carregado ou embutido a partir das respostas
+	defaultContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+)
+
+// Load lê e valida as variáveis de ambiente através de getenv (normalmente os.Getenv).
+// Todos os problemas encontrados são retornados juntos em um único erro.
+func Load(getenv func(string) string) (Config, error) {
+	var errs []error
+	cfg := Config{
+		Env:        getenv("APP_ENV"),
+		AdminToken: getenv("ADMIN_TOKEN"),
+		Database: DatabaseConfig{
+			Host:     getenv("DB_HOST"),
+			Port:     getenv("DB_PORT"),
+			Name:     getenv("DB_DATABASE"),
+			Username: getenv("DB_USERNAME"),
+			Password: getenv("DB_PASSWORD"),
+			Schema:   getenv("DB_SCHEMA"),
+			SSLMode:  getenv("DB_SSLMODE"),
+		},
+	}
+
+	port, err := parseInt(getenv, "PORT", defaultPort)
+	if err == nil && (port < 1 || port > 65535) {
+		err = fmt.Errorf("PORT must be between 1 and 65535, got %d", port)
+	}
+	errs = append(errs, err)
+	cfg.Port = port
+
+	cfg.Timezone, err = parseLocation(getenv, "APP_TIMEZONE", time.UTC)
+	errs = append(errs, err)
+
+	cfg.ShutdownTimeout, err = parseDuration(getenv, "SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	errs = append(errs, err)
+
+	cfg.MaintenanceMode, err = parseBool(getenv, "MAINTENANCE_MODE", false)
+	errs = append(errs, err)
+
+	cfg.PreferTrailingSlash, err = parseBool(getenv, "PREFER_TRAILING_SLASH", false)
+	errs = append(errs, err)
+
+	cfg.RelatorioRateLimit, err = parseInt(getenv, "RELATORIO_RATE_LIMIT", defaultRelatorioRateLimit)
+	if err == nil && cfg.RelatorioRateLimit < 0 {
+		err = fmt.Errorf("RELATORIO_RATE_LIMIT must not be negative, got %d", cfg.RelatorioRateLimit)
+	}
+	errs = append(errs, err)
+
+	cfg.TrustedProxies, err = parsePrefixes(getenv, "TRUSTED_PROXIES")
+	errs = append(errs, err)
+
+	cfg.MaxUnboundedRows, err = parseInt(getenv, "MAX_UNBOUNDED_ROWS", defaultMaxUnboundedRows)
+	if err == nil && cfg.MaxUnboundedRows < 0 {
+		err = fmt.Errorf("MAX_UNBOUNDED_ROWS must not be negative, got %d", cfg.MaxUnboundedRows)
+	}
+	errs = append(errs, err)
+
+	cfg.ReadRetries, err = parseInt(getenv, "READ_RETRIES", defaultReadRetries)
+	if err == nil && cfg.ReadRetries < 0 {
+		err = fmt.Errorf("READ_RETRIES must not be negative, got %d", cfg.ReadRetries)
+	}
+	errs = append(errs, err)
+
+	cfg.LogSampleRate, err = parseFloat(getenv, "LOG_SAMPLE_RATE", defaultLogSampleRate)
+	if err == nil && (cfg.LogSampleRate < 0 || cfg.LogSampleRate > 1) {
+		err = fmt.Errorf("LOG_SAMPLE_RATE must be between 0 and 1, got %g", cfg.LogSampleRate)
+	}
+	errs = append(errs, err)
+
+	cfg.CORSMaxAge, err = parseInt(getenv, "CORS_MAX_AGE", defaultCORSMaxAge)
+	if err == nil && cfg.CORSMaxAge < 0 {
+		err = fmt.Errorf("CORS_MAX_AGE must not be negative, got %d", cfg.CORSMaxAge)
+	}
+	errs = append(errs, err)
+
+	cfg.ContentSecurityPolicy = getenv("CONTENT_SECURITY_POLICY")
+	switch cfg.ContentSecurityPolicy {
+	case "":
+		cfg.ContentSecurityPolicy = defaultContentSecurityPolicy
+	case "off":
+		cfg.ContentSecurityPolicy = ""
+	}
+
+	cfg.Features, err = loadFeatures(getenv)
+	errs = append(errs, err)
+
+	cfg.Database.MaxOpenConns, err = parseInt(getenv, "DB_MAX_OPEN_CONNS", 0)
+	if err == nil && cfg.Database.MaxOpenConns < 0 {
+		err = fmt.Errorf("DB_MAX_OPEN_CONNS must not be negative, got %d", cfg.Database.MaxOpenConns)
+	}
+	errs = append(errs, err)
+
+	cfg.Database.PoolWait, err = parseDuration(getenv, "DB_POOL_WAIT", defaultPoolWait)
+	errs = append(errs, err)
+
+	cfg.Database.SaturationThreshold, err = parseFloat(getenv, "DB_SATURATION_THRESHOLD", defaultSaturation)
+	if err == nil && (cfg.Database.SaturationThreshold < 0 || cfg.Database.SaturationThreshold > 1) {
+		err = fmt.Errorf("DB_SATURATION_THRESHOLD must be between 0 and 1, got %g", cfg.Database.SaturationThreshold)
+	}
+	errs = append(errs, err)
+
+	cfg.Database.BreakerThreshold, err = parseInt(getenv, "DB_BREAKER_THRESHOLD", defaultBreakerThreshold)
+	if err == nil && cfg.Database.BreakerThreshold < 0 {
+		err = fmt.Errorf("DB_BREAKER_THRESHOLD must not be negative, got %d", cfg.Database.BreakerThreshold)
+	}
+	errs = append(errs, err)
+
+	cfg.Database.BreakerCooldown, err = parseDuration(getenv, "DB_BREAKER_COOLDOWN", defaultBreakerCooldown)
+	errs = append(errs, err)
+
+	errs = append(errs, cfg.Database.validate())
+
+	return cfg, errors.Join(errs...)
+}
+
+func (db DatabaseConfig) validate() error {
+	var errs []error
+	required := map[string]string{
+		"DB_HOST":     db.Host,
+		"DB_PORT":     db.Port,
+		"DB_DATABASE": db.Name,
+		"DB_USERNAME": db.Username,
+	}
+	for _, key := range []string{"DB_HOST", "DB_PORT", "DB_DATABASE", "DB_USERNAME"} {
+		if required[key] == "" {
+			errs = append(errs, fmt.Errorf("%s is required", key))
+		}
+	}
+	if db.Port != "" {
+		if _, err := strconv.Atoi(db.Port); err != nil {
+			errs = append(errs, fmt.Errorf("DB_PORT must be an integer, got %q", db.Port))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func parseInt(getenv func(string) string, key string, fallback int) (int, error) {
+	value := getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback, fmt.Errorf("%s must be an integer, got %q", key, value)
+	}
+	return n, nil
+}
+
+func parseFloat(getenv func(string) string, key string, fallback float64) (float64, error) {
+	value := getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback, fmt.Errorf("%s must be a number, got %q", key, value)
+	}
+	return f, nil
+}
+
+func parseBool(getenv func(string) string, key string, fallback bool) (bool, error) {
+	value := getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback, fmt.Errorf("%s must be a boolean, got %q", key, value)
+	}
+	return b, nil
+}
+
+func parseDuration(getenv func(string) string, key string, fallback time.Duration) (time.Duration, error) {
+	value := getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback, fmt.Errorf("%s must be a positive duration (e.g. 30s), got %q", key, value)
+	}
+	return d, nil
+}
+
+func parseLocation(getenv func(string) string, key string, fallback *time.Location) (*time.Location, error) {
+	value := getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return fallback, fmt.Errorf("%s must be an IANA timezone (e.g. America/Sao_Paulo), got %q", key, value)
+	}
+	return loc, nil
+}
+
+// Lista de IPs ou CIDRs separados por vírgula. Um IP isolado vira um prefixo de um único endereço.
+func parsePrefixes(getenv func(string) string, key string) ([]netip.Prefix, error) {
+	value := getenv(key)
+	if value == "" {
+		return nil, nil
+	}
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(item)
+			if addrErr != nil {
+				return nil, fmt.Errorf("%s must be a comma-separated list of IPs or CIDRs, got %q", key, item)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
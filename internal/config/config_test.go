@@ -0,0 +1,150 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func envFrom(values map[string]string) func(string) string {
+	return func(key string) string {
+		return values[key]
+	}
+}
+
+func validEnv() map[string]string {
+	return map[string]string{
+		"PORT":        "3000",
+		"DB_HOST":     "localhost",
+		"DB_PORT":     "5432",
+		"DB_DATABASE": "edna-db",
+		"DB_USERNAME": "admin",
+		"DB_PASSWORD": "password1234",
+	}
+}
+
+func TestLoadValidConfig(t *testing.T) {
+	env := validEnv()
+	env["SHUTDOWN_TIMEOUT"] = "30s"
+	env["MAINTENANCE_MODE"] = "true"
+	env["APP_TIMEZONE"] = "America/Sao_Paulo"
+
+	cfg, err := Load(envFrom(env))
+	if err != nil {
+		t.Fatalf("expected config to be valid; got %v", err)
+	}
+	if cfg.Port != 3000 {
+		t.Errorf("expected port 3000; got %d", cfg.Port)
+	}
+	if cfg.ShutdownTimeout != 30*time.Second {
+		t.Errorf("expected shutdown timeout 30s; got %s", cfg.ShutdownTimeout)
+	}
+	if !cfg.MaintenanceMode {
+		t.Error("expected maintenance mode to be enabled")
+	}
+	if cfg.Timezone.String() != "America/Sao_Paulo" {
+		t.Errorf("expected timezone America/Sao_Paulo; got %s", cfg.Timezone)
+	}
+	if cfg.TrustedProxies != nil {
+		t.Errorf("expected no trusted proxies by default; got %v", cfg.TrustedProxies)
+	}
+	if cfg.RelatorioRateLimit != defaultRelatorioRateLimit {
+		t.Errorf("expected default relatorio rate limit; got %d", cfg.RelatorioRateLimit)
+	}
+	if cfg.MaxUnboundedRows != defaultMaxUnboundedRows {
+		t.Errorf("expected default max unbounded rows; got %d", cfg.MaxUnboundedRows)
+	}
+	if cfg.ReadRetries != defaultReadRetries {
+		t.Errorf("expected default read retries; got %d", cfg.ReadRetries)
+	}
+	if cfg.LogSampleRate != defaultLogSampleRate {
+		t.Errorf("expected default log sample rate; got %g", cfg.LogSampleRate)
+	}
+	if cfg.CORSMaxAge != defaultCORSMaxAge {
+		t.Errorf("expected default CORS max age; got %d", cfg.CORSMaxAge)
+	}
+	if cfg.ContentSecurityPolicy != defaultContentSecurityPolicy {
+		t.Errorf("expected default content security policy; got %q", cfg.ContentSecurityPolicy)
+	}
+	if cfg.Database.MaxOpenConns != 0 || cfg.Database.PoolWait != defaultPoolWait {
+		t.Errorf("expected an unlimited pool with the default wait; got %d and %s", cfg.Database.MaxOpenConns, cfg.Database.PoolWait)
+	}
+	if cfg.Database.BreakerThreshold != defaultBreakerThreshold || cfg.Database.BreakerCooldown != defaultBreakerCooldown {
+		t.Errorf("expected default breaker settings; got %d and %s", cfg.Database.BreakerThreshold, cfg.Database.BreakerCooldown)
+	}
+	if cfg.Database.SaturationThreshold != defaultSaturation {
+		t.Errorf("expected default saturation threshold; got %g", cfg.Database.SaturationThreshold)
+	}
+	if cfg.Database.Host != "localhost" || cfg.Database.Name != "edna-db" {
+		t.Errorf("unexpected database config: %+v", cfg.Database)
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	env := validEnv()
+	env["PORT"] = "abc"
+	env["SHUTDOWN_TIMEOUT"] = "-1s"
+	env["MAINTENANCE_MODE"] = "talvez"
+	env["DB_PORT"] = "postgres"
+	env["APP_TIMEZONE"] = "Marte/Olympus"
+	env["MAX_UNBOUNDED_ROWS"] = "-5"
+	env["CORS_MAX_AGE"] = "10m"
+	env["LOG_SAMPLE_RATE"] = "1.5"
+	env["READ_RETRIES"] = "-1"
+	env["DB_SATURATION_THRESHOLD"] = "alto"
+	env["DB_MAX_OPEN_CONNS"] = "-2"
+	env["DB_POOL_WAIT"] = "0s"
+	env["TRUSTED_PROXIES"] = "10.0.0.1, nginx"
+	delete(env, "DB_HOST")
+
+	_, err := Load(envFrom(env))
+	if err == nil {
+		t.Fatal("expected config to be invalid")
+	}
+	for _, key := range []string{"PORT", "SHUTDOWN_TIMEOUT", "MAINTENANCE_MODE", "DB_PORT", "DB_HOST", "APP_TIMEZONE", "MAX_UNBOUNDED_ROWS", "CORS_MAX_AGE", "LOG_SAMPLE_RATE", "READ_RETRIES", "DB_SATURATION_THRESHOLD", "DB_MAX_OPEN_CONNS", "DB_POOL_WAIT", "TRUSTED_PROXIES"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("expected error to mention %s; got %q", key, err.Error())
+		}
+	}
+}
+
+func TestLoadTrustedProxies(t *testing.T) {
+	env := validEnv()
+	env["TRUSTED_PROXIES"] = "10.0.0.1, 172.16.0.0/12,::1"
+
+	cfg, err := Load(envFrom(env))
+	if err != nil {
+		t.Fatalf("expected config to be valid; got %v", err)
+	}
+	want := []string{"10.0.0.1/32", "172.16.0.0/12", "::1/128"}
+	if len(cfg.TrustedProxies) != len(want) {
+		t.Fatalf("expected %d trusted proxies; got %v", len(want), cfg.TrustedProxies)
+	}
+	for i, prefix := range cfg.TrustedProxies {
+		if prefix.String() != want[i] {
+			t.Errorf("expected trusted proxy %s; got %s", want[i], prefix)
+		}
+	}
+}
+
+func TestLoadFeatures(t *testing.T) {
+	env := validEnv()
+	env["FEATURE_REQUEST_LOG"] = "off"
+
+	cfg, err := Load(envFrom(env))
+	if err != nil {
+		t.Fatalf("expected config to be valid; got %v", err)
+	}
+	if cfg.Features.Enabled(FeatureRequestLog) {
+		t.Error("expected request_log to be disabled")
+	}
+	if !cfg.Features.Enabled(FeatureRelatorioRateLimit) {
+		t.Error("expected relatorio_rate_limit to keep its default")
+	}
+
+	env["FEATURE_RELATORIO_RATE_LIMIT"] = "sometimes"
+	_, err = Load(envFrom(env))
+	if err == nil || !strings.Contains(err.Error(), "FEATURE_RELATORIO_RATE_LIMIT") {
+		t.Errorf("expected error to mention FEATURE_RELATORIO_RATE_LIMIT; got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Funcionalidades opcionais, ligadas ou desligadas por deploy com FEATURE_<NOME>=on|off
+const (
+	// Log de cada requisição com método, caminho, status e duração
+	FeatureRequestLog = "request_log"
+	// Limite de requisições por cliente nas rotas de relatório
+	FeatureRelatorioRateLimit = "relatorio_rate_limit"
+	// Headers de segurança (nosniff, X-Frame-Options e Content-Security-Policy) nas respostas
+	FeatureSecurityHeaders = "security_headers"
+)
+
+// Valor de cada funcionalidade quando a variável não é definida
+var defaultFeatures = map[string]bool{
+	FeatureRequestLog:         true,
+	FeatureRelatorioRateLimit: true,
+	FeatureSecurityHeaders:    true,
+}
+
+// Features guarda o estado das funcionalidades opcionais. Funcionalidades ausentes
+// assumem o valor padrão, então um Features vazio equivale à configuração padrão.
+type Features map[string]bool
+
+// Indica se a funcionalidade está ligada
+func (f Features) Enabled(name string) bool {
+	if enabled, ok := f[name]; ok {
+		return enabled
+	}
+	return defaultFeatures[name]
+}
+
+// Retorna o estado de todas as funcionalidades conhecidas
+func (f Features) All() map[string]bool {
+	all := make(map[string]bool, len(defaultFeatures))
+	for name := range defaultFeatures {
+		all[name] = f.Enabled(name)
+	}
+	return all
+}
+
+func loadFeatures(getenv func(string) string) (Features, error) {
+	names := make([]string, 0, len(defaultFeatures))
+	for name := range defaultFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	features := make(Features, len(names))
+	var errs []error
+	for _, name := range names {
+		key := "FEATURE_" + strings.ToUpper(name)
+		switch value := strings.ToLower(getenv(key)); value {
+		case "":
+			features[name] = defaultFeatures[name]
+		case "on", "true", "1":
+			features[name] = true
+		case "off", "false", "0":
+			features[name] = false
+		default:
+			errs = append(errs, fmt.Errorf("%s must be on or off, got %q", key, value))
+			features[name] = defaultFeatures[name]
+		}
+	}
+	return features, errors.Join(errs...)
+}
@@ -5,19 +5,18 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"os"
 	"strconv"
 	"time"
 
+	"edna/internal/config"
+
 	_ "github.com/jackc/pgx/v5/stdlib"
-	_ "github.com/joho/godotenv/autoload"
 )
 
 // Service represents a service that interacts with a database.
 type Service interface {
-	// Health returns a map of health status information.
-	// The keys and values in the map are service-specific.
-	Health() map[string]string
+	// Health returns the health status information of the database.
+	Health() HealthStats
 
 	Conn() *sql.DB
 
@@ -26,83 +25,121 @@ type Service interface {
 	Close() error
 }
 
+// HealthStats holds the health status and connection pool statistics of the
+// database. Pool statistics are only filled in when the database is up.
+// Status is "up", "down" or "degraded" when the pool saturation (in use / max open)
+// reaches the configured threshold.
+type HealthStats struct {
+	Status            string `json:"status" xml:"status"`
+	Message           string `json:"message,omitempty" xml:"message,omitempty"`
+	Error             string `json:"error,omitempty" xml:"error,omitempty"`
+	OpenConnections   string `json:"open_connections,omitempty" xml:"open_connections,omitempty"`
+	InUse             string `json:"in_use,omitempty" xml:"in_use,omitempty"`
+	MaxOpen           string `json:"max_open,omitempty" xml:"max_open,omitempty"`
+	Saturation        string `json:"saturation,omitempty" xml:"saturation,omitempty"`
+	Idle              string `json:"idle,omitempty" xml:"idle,omitempty"`
+	WaitCount         string `json:"wait_count,omitempty" xml:"wait_count,omitempty"`
+	WaitDuration      string `json:"wait_duration,omitempty" xml:"wait_duration,omitempty"`
+	MaxIdleClosed     string `json:"max_idle_closed,omitempty" xml:"max_idle_closed,omitempty"`
+	MaxLifetimeClosed string `json:"max_lifetime_closed,omitempty" xml:"max_lifetime_closed,omitempty"`
+	// State of the circuit breaker in front of the queries (closed, open or half_open)
+	Breaker string `json:"breaker,omitempty" xml:"breaker,omitempty"`
+}
+
 type service struct {
-	db *sql.DB
+	db   *sql.DB
+	name string
+	// Saturation of the pool from which the database is reported as degraded
+	saturationThreshold float64
 }
 
-var (
-	database   = os.Getenv("DB_DATABASE")
-	password   = os.Getenv("DB_PASSWORD")
-	username   = os.Getenv("DB_USERNAME")
-	port       = os.Getenv("DB_PORT")
-	host       = os.Getenv("DB_HOST")
-	schema     = os.Getenv("DB_SCHEMA")
-	sslmode	   = os.Getenv("DB_SSLMODE")
-	dbInstance *service
-)
+var dbInstance *service
 
-func New() Service {
+func New(cfg config.DatabaseConfig) Service {
 	// Reuse Connection
 	if dbInstance != nil {
 		return dbInstance
 	}
-	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s&search_path=%s", username, password, host, port, database, sslmode, schema)
+	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s&search_path=%s", cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Name, cfg.SSLMode, cfg.Schema)
 	db, err := sql.Open("pgx", connStr)
 	if err != nil {
 		log.Fatal(err)
 	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	if cfg.MaxOpenConns > 0 {
+		// Mantém as conexões ociosas em vez de fechar as que passam do padrão (2) a cada
+		// devolução ao pool
+		db.SetMaxIdleConns(cfg.MaxOpenConns)
+	}
 	dbInstance = &service{
-		db: db,
+		db:                  db,
+		name:                cfg.Name,
+		saturationThreshold: cfg.SaturationThreshold,
 	}
 	return dbInstance
 }
 
 // Health checks the health of the database connection by pinging the database.
-// It returns a map with keys indicating various health statistics.
-func (s *service) Health() map[string]string {
+// It returns a HealthStats describing the connection pool.
+func (s *service) Health() HealthStats {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	stats := make(map[string]string)
+	var stats HealthStats
 
 	// Ping the database
 	err := s.db.PingContext(ctx)
 	if err != nil {
-		stats["status"] = "down"
-		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf("db down: %v", err) // Log the error and terminate the program
+		stats.Status = "down"
+		stats.Error = fmt.Sprintf("db down: %v", err)
+		log.Printf("db down: %v", err)
 		return stats
 	}
 
+	return poolHealth(s.db.Stats(), s.saturationThreshold)
+}
+
+// poolHealth describes a reachable database from its connection pool statistics.
+// A pool without a connection limit is never considered saturated.
+func poolHealth(dbStats sql.DBStats, saturationThreshold float64) HealthStats {
 	// Database is up, add more statistics
-	stats["status"] = "up"
-	stats["message"] = "It's healthy"
-
-	// Get database stats (like open connections, in use, idle, etc.)
-	dbStats := s.db.Stats()
-	stats["open_connections"] = strconv.Itoa(dbStats.OpenConnections)
-	stats["in_use"] = strconv.Itoa(dbStats.InUse)
-	stats["idle"] = strconv.Itoa(dbStats.Idle)
-	stats["wait_count"] = strconv.FormatInt(dbStats.WaitCount, 10)
-	stats["wait_duration"] = dbStats.WaitDuration.String()
-	stats["max_idle_closed"] = strconv.FormatInt(dbStats.MaxIdleClosed, 10)
-	stats["max_lifetime_closed"] = strconv.FormatInt(dbStats.MaxLifetimeClosed, 10)
+	var stats HealthStats
+	stats.Status = "up"
+	stats.Message = "It's healthy"
+
+	stats.OpenConnections = strconv.Itoa(dbStats.OpenConnections)
+	stats.InUse = strconv.Itoa(dbStats.InUse)
+	stats.Idle = strconv.Itoa(dbStats.Idle)
+	stats.WaitCount = strconv.FormatInt(dbStats.WaitCount, 10)
+	stats.WaitDuration = dbStats.WaitDuration.String()
+	stats.MaxIdleClosed = strconv.FormatInt(dbStats.MaxIdleClosed, 10)
+	stats.MaxLifetimeClosed = strconv.FormatInt(dbStats.MaxLifetimeClosed, 10)
 
 	// Evaluate stats to provide a health message
 	if dbStats.OpenConnections > 40 { // Assuming 50 is the max for this example
-		stats["message"] = "The database is experiencing heavy load."
+		stats.Message = "The database is experiencing heavy load."
 	}
 
 	if dbStats.WaitCount > 1000 {
-		stats["message"] = "The database has a high number of wait events, indicating potential bottlenecks."
+		stats.Message = "The database has a high number of wait events, indicating potential bottlenecks."
 	}
 
 	if dbStats.MaxIdleClosed > int64(dbStats.OpenConnections)/2 {
-		stats["message"] = "Many idle connections are being closed, consider revising the connection pool settings."
+		stats.Message = "Many idle connections are being closed, consider revising the connection pool settings."
 	}
 
 	if dbStats.MaxLifetimeClosed > int64(dbStats.OpenConnections)/2 {
-		stats["message"] = "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
+		stats.Message = "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
+	}
+
+	if dbStats.MaxOpenConnections > 0 {
+		saturation := float64(dbStats.InUse) / float64(dbStats.MaxOpenConnections)
+		stats.MaxOpen = strconv.Itoa(dbStats.MaxOpenConnections)
+		stats.Saturation = strconv.FormatFloat(saturation, 'f', 2, 64)
+		if saturationThreshold > 0 && saturation >= saturationThreshold {
+			stats.Status = "degraded"
+			stats.Message = fmt.Sprintf("The connection pool is %.0f%% saturated (%d of %d connections in use).", saturation*100, dbStats.InUse, dbStats.MaxOpenConnections)
+		}
 	}
 
 	return stats
@@ -117,6 +154,6 @@ func (s *service) Conn() *sql.DB {
 // If the connection is successfully closed, it returns nil.
 // If an error occurs while closing the connection, it returns the error.
 func (s *service) Close() error {
-	log.Printf("Disconnected from database: %s", database)
+	log.Printf("Disconnected from database: %s", s.name)
 	return s.db.Close()
 }
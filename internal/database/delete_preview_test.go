@@ -0,0 +1,239 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"edna/internal/model"
+	"edna/internal/services/cliente"
+	"edna/internal/services/fornecedor"
+	"edna/internal/services/lote"
+	"edna/internal/services/oferta"
+	"edna/internal/services/produto"
+	"edna/internal/services/venda"
+	"edna/internal/types"
+	"edna/internal/util"
+)
+
+var migrations = os.DirFS("../../migrations")
+
+var (
+	createTableRe = regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+	queryTableRe  = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+(\w+)`)
+)
+
+// unknownTables returns the tables referenced in query (after FROM or JOIN) that no
+// `*.up.sql` migration creates. Names are compared case-insensitively, like PostgreSQL
+// does with unquoted identifiers.
+func unknownTables(query string) ([]string, error) {
+	files, err := fs.Glob(migrations, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string]bool)
+	for _, file := range files {
+		content, err := fs.ReadFile(migrations, file)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range createTableRe.FindAllStringSubmatch(string(content), -1) {
+			tables[strings.ToLower(match[1])] = true
+		}
+	}
+
+	var unknown []string
+	for _, match := range queryTableRe.FindAllStringSubmatch(query, -1) {
+		if !tables[strings.ToLower(match[1])] {
+			unknown = append(unknown, match[1])
+		}
+	}
+	return unknown, nil
+}
+
+var errRecorded = errors.New("query recorded")
+
+// recordingConnector opens connections that fail every query after recording its SQL,
+// so the queries a store runs can be checked without a database.
+type recordingConnector struct {
+	queries *[]string
+}
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return recordingConn(c), nil
+}
+func (c recordingConnector) Driver() driver.Driver { return nil }
+
+type recordingConn recordingConnector
+
+func (c recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	*c.queries = append(*c.queries, query)
+	return nil, errRecorded
+}
+func (c recordingConn) Prepare(string) (driver.Stmt, error) { return nil, errRecorded }
+func (c recordingConn) Close() error                        { return nil }
+func (c recordingConn) Begin() (driver.Tx, error)           { return nil, errRecorded }
+
+type deletePreviewer interface {
+	DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error)
+}
+
+// Inserts one row and returns the id from its RETURNING clause.
+func insert(t *testing.T, db *sql.DB, query string, args ...any) int64 {
+	t.Helper()
+	var id int64
+	if err := db.QueryRow(query, args...).Scan(&id); err != nil {
+		t.Fatalf("error seeding %q. Err: %v", query, err)
+	}
+	return id
+}
+
+func migrate(t *testing.T, db *sql.DB) {
+	t.Helper()
+	files, err := fs.Glob(migrations, "*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		content, err := fs.ReadFile(migrations, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSpace(string(content)) == "" {
+			continue
+		}
+		if _, err := db.Exec(string(content)); err != nil {
+			t.Fatalf("error applying %s. Err: %v", file, err)
+		}
+	}
+}
+
+func TestDeletePreview(t *testing.T) {
+	cases := []struct {
+		name  string
+		store func(db *util.DB) deletePreviewer
+		// Row previewed, picked from the ids seeded below
+		id        func(ids map[string]int64) int64
+		cascata   map[string]int64
+		bloqueios map[string]int64
+	}{
+		{
+			name:    "cliente",
+			store:   func(db *util.DB) deletePreviewer { return cliente.NewStore(db) },
+			id:      func(ids map[string]int64) int64 { return ids["cliente"] },
+			cascata: map[string]int64{"venda": 1, "item_venda": 1, "aplica_oferta": 1},
+		},
+		{
+			name:      "fornecedor",
+			store:     func(db *util.DB) deletePreviewer { return fornecedor.NewStore(db) },
+			id:        func(ids map[string]int64) int64 { return ids["fornecedor"] },
+			cascata:   map[string]int64{"lote": 2},
+			bloqueios: map[string]int64{"item_venda": 1},
+		},
+		{
+			name:    "oferta",
+			store:   func(db *util.DB) deletePreviewer { return oferta.NewStore(db) },
+			id:      func(ids map[string]int64) int64 { return ids["oferta"] },
+			cascata: map[string]int64{"contem_item_oferta": 1, "aplica_oferta": 1},
+		},
+		{
+			name:    "venda",
+			store:   func(db *util.DB) deletePreviewer { return venda.NewStore(db) },
+			id:      func(ids map[string]int64) int64 { return ids["venda"] },
+			cascata: map[string]int64{"item_venda": 1, "aplica_oferta": 1},
+		},
+		{
+			name:      "produto",
+			store:     func(db *util.DB) deletePreviewer { return produto.NewStore(db) },
+			id:        func(ids map[string]int64) int64 { return ids["produto"] },
+			cascata:   map[string]int64{"produtocomercial": 1, "lote": 2},
+			bloqueios: map[string]int64{"item_venda": 1, "contem_item_oferta": 1},
+		},
+		{
+			name:      "sold lote",
+			store:     func(db *util.DB) deletePreviewer { return lote.NewStore(db) },
+			id:        func(ids map[string]int64) int64 { return ids["lote_vendido"] },
+			cascata:   map[string]int64{},
+			bloqueios: map[string]int64{"item_venda": 1},
+		},
+		{
+			name:    "unsold lote",
+			store:   func(db *util.DB) deletePreviewer { return lote.NewStore(db) },
+			id:      func(ids map[string]int64) int64 { return ids["lote"] },
+			cascata: map[string]int64{},
+		},
+	}
+
+	// Without a database, at least check that every table queried exists in the migrations
+	var queries []string
+	recorder := sql.OpenDB(recordingConnector{queries: &queries})
+	defer recorder.Close()
+	for _, tc := range cases {
+		queries = queries[:0]
+		tc.store(&util.DB{DB: recorder}).DeletePreview(context.Background(), 1)
+		if len(queries) == 0 {
+			t.Fatalf("%s: expected the preview to run a query", tc.name)
+		}
+		for _, query := range queries {
+			unknown, err := unknownTables(query)
+			if err != nil {
+				t.Fatalf("error reading migrations. Err: %v", err)
+			}
+			if len(unknown) > 0 {
+				t.Errorf("%s: delete preview query uses tables not created by any migration: %v", tc.name, unknown)
+			}
+		}
+	}
+
+	requireDatabase(t)
+	db, err := sql.Open("pgx", fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", testConfig.Username, testConfig.Password, testConfig.Host, testConfig.Port, testConfig.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	migrate(t, db)
+
+	// One of each entity, linked so that every cascade and block is counted once, plus a
+	// second lote of the same produto that was never sold
+	ids := make(map[string]int64)
+	ids["fornecedor"] = insert(t, db, `INSERT INTO Fornecedor (CNPJ, nome) VALUES ('00000000000191', 'Fornecedor Preview') RETURNING id_fornecedor`)
+	ids["produto"] = insert(t, db, `INSERT INTO Produto (nome, categoria, marca) VALUES ('Produto Preview', 'Bebidas', 'Teste') RETURNING id_produto`)
+	insert(t, db, `INSERT INTO ProdutoComercial (id_produto, preco_venda) VALUES ($1, 10) RETURNING id_produto`, ids["produto"])
+	ids["lote_vendido"] = insert(t, db, `INSERT INTO Lote (id_fornecedor, id_produto, data_fornecimento, preco_unitario, quantidade_inicial) VALUES ($1, $2, '2025-01-10', 5, 10) RETURNING id_lote`, ids["fornecedor"], ids["produto"])
+	ids["lote"] = insert(t, db, `INSERT INTO Lote (id_fornecedor, id_produto, data_fornecimento, preco_unitario, quantidade_inicial) VALUES ($1, $2, '2025-01-20', 5, 10) RETURNING id_lote`, ids["fornecedor"], ids["produto"])
+	ids["cliente"] = insert(t, db, `INSERT INTO Cliente (nome, CPF, data_nascimento) VALUES ('Cliente Preview', '00000000191', '1990-01-01') RETURNING id_cliente`)
+	funcionario := insert(t, db, `INSERT INTO Funcionario (nome, CPF, tipo, expediente, data_contratacao, salario) VALUES ('Funcionario Preview', '00000000272', 'garcom', 'noite', '2024-01-01', 2000) RETURNING id_funcionario`)
+	ids["venda"] = insert(t, db, `INSERT INTO Venda (id_cliente, id_funcionario) VALUES ($1, $2) RETURNING id_venda`, ids["cliente"], funcionario)
+	itemVenda := insert(t, db, `INSERT INTO item_venda (id_venda, id_lote, quantidade, valor_unitario) VALUES ($1, $2, 1, 10) RETURNING id_item_venda`, ids["venda"], ids["lote_vendido"])
+	ids["oferta"] = insert(t, db, `INSERT INTO Oferta (nome, valor_fixo) VALUES ('Oferta Preview', 8) RETURNING id_oferta`)
+	insert(t, db, `INSERT INTO contem_item_oferta (id_oferta, id_produto, quantidade) VALUES ($1, $2, 1) RETURNING id_oferta`, ids["oferta"], ids["produto"])
+	insert(t, db, `INSERT INTO aplica_oferta (id_oferta, id_venda, id_item_venda) VALUES ($1, $2, $3) RETURNING id_aplica_oferta`, ids["oferta"], ids["venda"], itemVenda)
+
+	ctx := context.Background()
+	for _, tc := range cases {
+		store := tc.store(&util.DB{DB: db})
+		preview, err := store.DeletePreview(ctx, tc.id(ids))
+		if err != nil {
+			t.Errorf("%s: error previewing delete. Err: %v", tc.name, err)
+			continue
+		}
+		if !maps.Equal(preview.Cascata, tc.cascata) {
+			t.Errorf("%s: expected cascata %v; got %v", tc.name, tc.cascata, preview.Cascata)
+		}
+		if !maps.Equal(preview.Bloqueios, tc.bloqueios) {
+			t.Errorf("%s: expected bloqueios %v; got %v", tc.name, tc.bloqueios, preview.Bloqueios)
+		}
+
+		if _, err := store.DeletePreview(ctx, -1); !errors.Is(err, types.ErrNotFound) {
+			t.Errorf("%s: expected ErrNotFound for a missing row; got %v", tc.name, err)
+		}
+	}
+}
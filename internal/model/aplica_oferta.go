@@ -1,16 +1,16 @@
 package model
 
 type AplicaOferta struct {
-	IDAplicaOferta int64 `json:"id_aplica_oferta"`
-	IDOferta       int64 `json:"id_oferta"`
-	IDVenda        int64 `json:"id_venda"`
-	IDItemVenda    int64 `json:"id_item_venda"`
+	IDAplicaOferta int64 `json:"id_aplica_oferta" xml:"id_aplica_oferta"`
+	IDOferta       int64 `json:"id_oferta" xml:"id_oferta"`
+	IDVenda        int64 `json:"id_venda" xml:"id_venda"`
+	IDItemVenda    int64 `json:"id_item_venda" xml:"id_item_venda"`
 }
 
 type AplicaOfertaResponse struct {
-	IDOferta    int64 `json:"id_oferta"`
-	IDVenda     int64 `json:"id_venda"`
-	IDItemVenda int64 `json:"id_item_venda"`
+	IDOferta    int64 `json:"id_oferta" xml:"id_oferta"`
+	IDVenda     int64 `json:"id_venda" xml:"id_venda"`
+	IDItemVenda int64 `json:"id_item_venda" xml:"id_item_venda"`
 }
 
 func (aor AplicaOfertaResponse) ToAplicaOferta() AplicaOferta {
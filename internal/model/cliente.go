@@ -1,19 +1,15 @@
 package model
 
-import (
-	"time"
-)
-
 type Cliente struct {
-	Id             int64      `json:"id"`
-	Nome           string     `json:"nome"`
-	CPF            *string    `json:"cpf"`
-	DataNascimento *time.Time `json:"data_nascimento"`
+	Id             int64   `json:"id" xml:"id"`
+	Nome           string  `json:"nome" xml:"nome"`
+	CPF            *string `json:"cpf" xml:"cpf"`
+	DataNascimento *Date   `json:"data_nascimento" xml:"data_nascimento"`
 }
 
 type ClienteWithSaldo struct {
 	Cliente
-	SaldoDevedor float32 `json:"saldo_devedor"`
+	SaldoDevedor float32 `json:"saldo_devedor" xml:"saldo_devedor"`
 }
 
 func (c *Cliente) ToClienteWithSaldo(saldo float32) ClienteWithSaldo {
@@ -24,9 +20,9 @@ func (c *Cliente) ToClienteWithSaldo(saldo float32) ClienteWithSaldo {
 }
 
 type ClienteCreate struct {
-	Nome           string     `json:"nome"`
-	CPF            *string    `json:"cpf"`
-	DataNascimento *time.Time `json:"data_nascimento"` // Espera-se "YYYY-MM-DD" ou formato RFC3339
+	Nome           string  `json:"nome" xml:"nome" validate:"required,max=50"`
+	CPF            *string `json:"cpf" xml:"cpf" validate:"max=11"`
+	DataNascimento *Date   `json:"data_nascimento" xml:"data_nascimento"` // Espera-se "YYYY-MM-DD" ou formato RFC3339
 }
 
 func (cc ClienteCreate) ToCliente() Cliente {
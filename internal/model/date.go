@@ -0,0 +1,96 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"edna/internal/util"
+)
+
+// Date representa uma coluna `date` do banco. Diferente de time.Time, é serializada
+// em JSON apenas com a data (YYYY-MM-DD); horários continuam usando time.Time.
+type Date struct {
+	time.Time
+}
+
+// Cria uma Date com o dia de t, à meia-noite UTC. O fuso não importa: só o dia é
+// serializado e enviado ao banco (ver Value).
+func NewDate(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	return d.Format(util.DateLayout)
+}
+
+// MarshalJSON e UnmarshalJSON precisam ser definidos aqui, senão os de time.Time
+// (promovidos pelo campo embutido) teriam prioridade sobre MarshalText/UnmarshalText.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("invalid date %s, expected a string", b)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Aceita YYYY-MM-DD ou RFC3339. No RFC3339 vale o dia escrito no texto, ignorando
+// horário e fuso, então "2024-03-10" e "2024-03-10T00:00:00Z" resultam na mesma data.
+func (d *Date) UnmarshalText(text []byte) error {
+	if t, err := util.ParseDate(string(text), time.UTC); err == nil {
+		*d = NewDate(t)
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, string(text))
+	if err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC3339", text)
+	}
+	*d = NewDate(t)
+	return nil
+}
+
+// Formato usado no JSON Schema de /schemas/{entity}
+func (d Date) SchemaFormat() string {
+	return "date"
+}
+
+// Envia apenas a data ao banco, sem horário nem fuso que possam mudar o dia
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Date) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = NewDate(v)
+	case string:
+		return d.scanString(v)
+	case []byte:
+		return d.scanString(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into Date", src)
+	}
+	return nil
+}
+
+func (d *Date) scanString(value string) error {
+	t, err := util.ParseDate(value, time.UTC)
+	if err != nil {
+		return err
+	}
+	*d = NewDate(t)
+	return nil
+}
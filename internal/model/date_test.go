@@ -0,0 +1,73 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateMarshalsWithoutTime(t *testing.T) {
+	validade := NewDate(time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC))
+	lote := Lote{
+		Id:               1,
+		DataFornecimento: NewDate(time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)),
+		Validade:         &validade,
+	}
+
+	b, err := json.Marshal(lote)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["data_fornecimento"] != "2024-03-10" {
+		t.Errorf("expected data_fornecimento 2024-03-10; got %v", got["data_fornecimento"])
+	}
+	if got["validade"] != "2025-01-31" {
+		t.Errorf("expected validade 2025-01-31; got %v", got["validade"])
+	}
+
+	lote.Validade = nil
+	b, _ = json.Marshal(lote)
+	json.Unmarshal(b, &got)
+	if got["validade"] != nil {
+		t.Errorf("expected null validade; got %v", got["validade"])
+	}
+}
+
+func TestDateScan(t *testing.T) {
+	var d Date
+	if err := d.Scan(time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.String() != "2024-03-10" {
+		t.Errorf("expected 2024-03-10; got %s", d)
+	}
+	v, _ := d.Value()
+	if v != "2024-03-10" {
+		t.Errorf("expected value 2024-03-10; got %v", v)
+	}
+}
+
+func TestDateAcceptsDateOnlyAndRFC3339(t *testing.T) {
+	var dateOnly, rfc3339 ClienteCreate
+	if err := json.Unmarshal([]byte(`{"data_nascimento": "1967-06-05"}`), &dateOnly); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"data_nascimento": "1967-06-05T00:00:00Z"}`), &rfc3339); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !dateOnly.DataNascimento.Equal(rfc3339.DataNascimento.Time) {
+		t.Errorf("expected both formats to produce the same date; got %s and %s", dateOnly.DataNascimento.Time, rfc3339.DataNascimento.Time)
+	}
+	if got := dateOnly.DataNascimento.String(); got != "1967-06-05" {
+		t.Errorf("expected the day written in the text; got %s", got)
+	}
+
+	var invalid ClienteCreate
+	if err := json.Unmarshal([]byte(`{"data_nascimento": "05/06/1967"}`), &invalid); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+// Resumo de um DELETE executado com `?dry_run=true`: o registro não é removido,
+// apenas são contadas as linhas que seriam apagadas em cascata em cada tabela.
+type DeletePreview struct {
+	Entidade string           `json:"entidade" xml:"entidade"`
+	Id       int64            `json:"id" xml:"id"`
+	Cascata  map[string]int64 `json:"cascata"`
+	// Linhas que referenciam o registro com ON DELETE RESTRICT, por tabela. Se houver
+	// alguma o DELETE real falha.
+	Bloqueios map[string]int64 `json:"bloqueios,omitempty"`
+}
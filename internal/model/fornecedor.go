@@ -2,14 +2,14 @@ package model
 
 
 type Fornecedor struct {
-	Id int64 `json:"id"`
-	Nome string `json:"nome"`
-	CNPJ string `json:"cnpj"`
+	Id int64 `json:"id" xml:"id"`
+	Nome string `json:"nome" xml:"nome"`
+	CNPJ string `json:"cnpj" xml:"cnpj"`
 }
 
 type FornecedorCreate struct {
-	Nome string `json:"nome"`
-	CNPJ string `json:"cnpj"`
+	Nome string `json:"nome" xml:"nome" validate:"required,max=50"`
+	CNPJ string `json:"cnpj" xml:"cnpj" validate:"max=14"`
 }
 
 func (fc FornecedorCreate) ToFornecedor() Fornecedor {
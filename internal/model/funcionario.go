@@ -1,22 +1,115 @@
 package model
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Tipo de funcionário, espelha o enum tipo_de_funcionario do banco
+type TipoFuncionario string
+
+const (
+	TipoGarcom     TipoFuncionario = "garcom"
+	TipoSeguranca  TipoFuncionario = "seguranca"
+	TipoCaixa      TipoFuncionario = "caixa"
+	TipoFaxineiro  TipoFuncionario = "faxineiro"
+	TipoBalconista TipoFuncionario = "balconista"
+)
+
+// Valores aceitos para TipoFuncionario
+var TiposFuncionario = []TipoFuncionario{TipoGarcom, TipoSeguranca, TipoCaixa, TipoFaxineiro, TipoBalconista}
+
+func ParseTipoFuncionario(s string) (TipoFuncionario, error) {
+	t := TipoFuncionario(s)
+	if !t.Valid() {
+		return "", fmt.Errorf("Invalid tipo `%s`, expected one of: %s", s, joinEnum(TiposFuncionario))
+	}
+	return t, nil
+}
+
+func (t TipoFuncionario) String() string {
+	return string(t)
+}
+
+func (t TipoFuncionario) Valid() bool {
+	for _, v := range TiposFuncionario {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Turno de trabalho, espelha o enum tipo_de_expediente do banco
+type Expediente string
+
+const (
+	ExpedienteManha     Expediente = "manha"
+	ExpedienteTarde     Expediente = "tarde"
+	ExpedienteNoite     Expediente = "noite"
+	ExpedienteMadrugada Expediente = "madrugada"
+)
+
+// Valores aceitos para Expediente
+var Expedientes = []Expediente{ExpedienteManha, ExpedienteTarde, ExpedienteNoite, ExpedienteMadrugada}
+
+func ParseExpediente(s string) (Expediente, error) {
+	e := Expediente(s)
+	if !e.Valid() {
+		return "", fmt.Errorf("Invalid expediente `%s`, expected one of: %s", s, joinEnum(Expedientes))
+	}
+	return e, nil
+}
+
+func (e Expediente) String() string {
+	return string(e)
+}
+
+func (e Expediente) Valid() bool {
+	for _, v := range Expedientes {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func joinEnum[T ~string](values []T) string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = string(v)
+	}
+	return strings.Join(strs, ", ")
+}
+
 type Funcionario struct {
-	Id              int64   `json:"id"`
-	Nome            string  `json:"nome"`
-	CPF             string  `json:"CPF"`
-	Tipo            string  `json:"tipo"`
-	Expediente      string  `json:"expediente"`
-	Salario         float64 `json:"salario"`
-	DataContratacao string  `json:"data_contratacao"`
+	Id              int64           `json:"id" xml:"id"`
+	Nome            string          `json:"nome" xml:"nome"`
+	CPF             string          `json:"CPF" xml:"CPF"`
+	Tipo            TipoFuncionario `json:"tipo" xml:"tipo"`
+	Expediente      Expediente      `json:"expediente" xml:"expediente"`
+	Salario         float64         `json:"salario" xml:"salario"`
+	DataContratacao string          `json:"data_contratacao" xml:"data_contratacao"`
 }
 
 type FuncionarioCreate struct {
-	Nome            string  `json:"nome"`
-	CPF             string  `json:"CPF"`
-	Tipo            string  `json:"tipo"`
-	Expediente      string  `json:"expediente"`
-	Salario         float64 `json:"salario"`
-	DataContratacao string  `json:"data_contratacao"`
+	Nome            string          `json:"nome" xml:"nome" validate:"required,max=50"`
+	CPF             string          `json:"CPF" xml:"CPF" validate:"required,max=11"`
+	Tipo            TipoFuncionario `json:"tipo" xml:"tipo" validate:"required"`
+	Expediente      Expediente      `json:"expediente" xml:"expediente" validate:"required"`
+	Salario         float64         `json:"salario" xml:"salario" validate:"min=0"`
+	DataContratacao string          `json:"data_contratacao" xml:"data_contratacao" validate:"required"`
+}
+
+// Verifica se tipo e expediente são valores aceitos pelo banco
+func (fc FuncionarioCreate) Validate() error {
+	if _, err := ParseTipoFuncionario(string(fc.Tipo)); err != nil {
+		return err
+	}
+	if _, err := ParseExpediente(string(fc.Expediente)); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (fc FuncionarioCreate) ToFuncionario() Funcionario {
@@ -0,0 +1,45 @@
+package model
+
+import "testing"
+
+func TestParseTipoFuncionario(t *testing.T) {
+	for _, v := range []string{"garcom", "seguranca", "caixa", "faxineiro", "balconista"} {
+		tipo, err := ParseTipoFuncionario(v)
+		if err != nil {
+			t.Errorf("expected %q to be valid; got %v", v, err)
+		}
+		if tipo.String() != v {
+			t.Errorf("expected %q; got %q", v, tipo.String())
+		}
+	}
+
+	for _, v := range []string{"", "gerente", "Garcom"} {
+		if _, err := ParseTipoFuncionario(v); err == nil {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestParseExpediente(t *testing.T) {
+	for _, v := range []string{"manha", "tarde", "noite", "madrugada"} {
+		if _, err := ParseExpediente(v); err != nil {
+			t.Errorf("expected %q to be valid; got %v", v, err)
+		}
+	}
+
+	if _, err := ParseExpediente("integral"); err == nil {
+		t.Error("expected \"integral\" to be invalid")
+	}
+}
+
+func TestFuncionarioCreateValidate(t *testing.T) {
+	valid := FuncionarioCreate{Tipo: TipoCaixa, Expediente: ExpedienteNoite}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected payload to be valid; got %v", err)
+	}
+
+	invalid := FuncionarioCreate{Tipo: "gerente", Expediente: ExpedienteNoite}
+	if err := invalid.Validate(); err == nil {
+		t.Error("expected invalid tipo to be rejected")
+	}
+}
@@ -1,15 +1,15 @@
 package model
 
 type ItemOferta struct {
-	Quantidade int64 `json:"quantidade"`
-	IDProduto  int64 `json:"id_produto"`
-	IDOferta   int64 `json:"id_oferta"`
+	Quantidade int64 `json:"quantidade" xml:"quantidade"`
+	IDProduto  int64 `json:"id_produto" xml:"id_produto"`
+	IDOferta   int64 `json:"id_oferta" xml:"id_oferta"`
 }
 
 type ItemOfertaCreate struct {
-	Quantidade int64 `json:"quantidade"`
-	IDProduto  int64 `json:"id_produto"`
-	IDOferta   int64 `json:"id_oferta"`
+	Quantidade int64 `json:"quantidade" xml:"quantidade" validate:"gt=0"`
+	IDProduto  int64 `json:"id_produto" xml:"id_produto"`
+	IDOferta   int64 `json:"id_oferta" xml:"id_oferta"`
 }
 
 func (ioc ItemOfertaCreate) ToItemOferta() ItemOferta {
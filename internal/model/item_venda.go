@@ -1,18 +1,18 @@
 package model
 
 type ItemVenda struct {
-	IDItemVenda   int64   `json:"id_item_venda"`
-	IDVenda       int64   `json:"id_venda"`
-	IDLote        int64   `json:"id_lote"`
-	Quantidade    int64   `json:"quantidade"`
-	ValorUnitario float64 `json:"valor_unitario"`
+	IDItemVenda   int64   `json:"id_item_venda" xml:"id_item_venda"`
+	IDVenda       int64   `json:"id_venda" xml:"id_venda"`
+	IDLote        int64   `json:"id_lote" xml:"id_lote"`
+	Quantidade    int64   `json:"quantidade" xml:"quantidade"`
+	ValorUnitario float64 `json:"valor_unitario" xml:"valor_unitario"`
 }
 
 type ItemVendaCreate struct {
-	IDVenda       int64   `json:"id_venda"`
-	IDLote        int64   `json:"id_lote"`
-	Quantidade    int64   `json:"quantidade"`
-	ValorUnitario float64 `json:"valor_unitario"`
+	IDVenda       int64   `json:"id_venda" xml:"id_venda" validate:"required"`
+	IDLote        int64   `json:"id_lote" xml:"id_lote" validate:"required"`
+	Quantidade    int64   `json:"quantidade" xml:"quantidade" validate:"gt=0"`
+	ValorUnitario float64 `json:"valor_unitario" xml:"valor_unitario" validate:"min=0"`
 }
 
 func (ivc ItemVendaCreate) ToItemVenda() ItemVenda {
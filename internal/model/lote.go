@@ -1,28 +1,24 @@
 package model
 
-import (
-	"time"
-)
-
 type Lote struct {
-	Id                int64      `json:"id_lote"`
-	IdFornecedor      int64      `json:"id_fornecedor"`
-	IdProduto         int64      `json:"id_produto"`
-	DataFornecimento  time.Time  `json:"data_fornecimento"`
-	Validade          *time.Time `json:"validade"`
-	PrecoUnitario     float64    `json:"preco_unitario"`
-	Estragados        *int       `json:"estragados"`
-	QuantidadeInicial *int       `json:"quantidade_inicial"`
+	Id                int64   `json:"id_lote" xml:"id_lote"`
+	IdFornecedor      int64   `json:"id_fornecedor" xml:"id_fornecedor"`
+	IdProduto         int64   `json:"id_produto" xml:"id_produto"`
+	DataFornecimento  Date    `json:"data_fornecimento" xml:"data_fornecimento"`
+	Validade          *Date   `json:"validade" xml:"validade"`
+	PrecoUnitario     float64 `json:"preco_unitario" xml:"preco_unitario"`
+	Estragados        *int    `json:"estragados" xml:"estragados"`
+	QuantidadeInicial *int    `json:"quantidade_inicial" xml:"quantidade_inicial"`
 }
 
 type LoteCreate struct {
-	IdFornecedor      int64      `json:"id_fornecedor"`
-	IdProduto         int64      `json:"id_produto"`
-	DataFornecimento  time.Time  `json:"data_fornecimento"`
-	Validade          *time.Time `json:"validade"`
-	PrecoUnitario     float64    `json:"preco_unitario"`
-	Estragados        *int       `json:"estragados"`
-	QuantidadeInicial *int       `json:"quantidade_inicial"`
+	IdFornecedor      int64   `json:"id_fornecedor" xml:"id_fornecedor" validate:"required"`
+	IdProduto         int64   `json:"id_produto" xml:"id_produto" validate:"required"`
+	DataFornecimento  Date    `json:"data_fornecimento" xml:"data_fornecimento" validate:"required"`
+	Validade          *Date   `json:"validade" xml:"validade"`
+	PrecoUnitario     float64 `json:"preco_unitario" xml:"preco_unitario" validate:"gt=0"`
+	Estragados        *int    `json:"estragados" xml:"estragados" validate:"min=0"`
+	QuantidadeInicial *int    `json:"quantidade_inicial" xml:"quantidade_inicial" validate:"gt=0"`
 }
 
 func (lc LoteCreate) ToLote() Lote {
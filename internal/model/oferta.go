@@ -1,25 +1,21 @@
 package model
 
-import (
-	"time"
-)
-
 type Oferta struct {
-	Id                 int64      `json:"id_oferta"`
-	Nome               string     `json:"nome"`
-	DataCriacao        time.Time  `json:"data_criacao"`
-	DataInicio         *time.Time `json:"data_inicio"`
-	DataFim            *time.Time `json:"data_fim"`
-	ValorFixo          *float64   `json:"valor_fixo"`
-	PercentualDesconto *int       `json:"percentual_desconto"`
+	Id                 int64    `json:"id_oferta" xml:"id_oferta"`
+	Nome               string   `json:"nome" xml:"nome"`
+	DataCriacao        Date     `json:"data_criacao" xml:"data_criacao"`
+	DataInicio         *Date    `json:"data_inicio" xml:"data_inicio"`
+	DataFim            *Date    `json:"data_fim" xml:"data_fim"`
+	ValorFixo          *float64 `json:"valor_fixo" xml:"valor_fixo"`
+	PercentualDesconto *int     `json:"percentual_desconto" xml:"percentual_desconto"`
 }
 
 type OfertaCreate struct {
-	Nome               string     `json:"nome"`
-	DataInicio         *time.Time `json:"data_inicio"`
-	DataFim            *time.Time `json:"data_fim"`
-	ValorFixo          *float64   `json:"valor_fixo"`
-	PercentualDesconto *int       `json:"percentual_desconto"`
+	Nome               string   `json:"nome" xml:"nome" validate:"required,max=50"`
+	DataInicio         *Date    `json:"data_inicio" xml:"data_inicio"`
+	DataFim            *Date    `json:"data_fim" xml:"data_fim"`
+	ValorFixo          *float64 `json:"valor_fixo" xml:"valor_fixo" validate:"min=0"`
+	PercentualDesconto *int     `json:"percentual_desconto" xml:"percentual_desconto" validate:"min=0,max=100"`
 }
 
 func (oc OfertaCreate) ToOferta() Oferta {
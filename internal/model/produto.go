@@ -1,32 +1,32 @@
 package model
 
 type Produto struct {
-	Id int64 `json:"id"`
-	Nome string `json:"nome"`
-	Categoria string `json:"categoria"`
-	Marca string `json:"marca"`
+	Id int64 `json:"id" xml:"id"`
+	Nome string `json:"nome" xml:"nome"`
+	Categoria string `json:"categoria" xml:"categoria"`
+	Marca string `json:"marca" xml:"marca"`
 }
 
 type Comercial struct {
 	Produto
-	PrecoVenda float32 `json:"preco_venda"`
+	PrecoVenda float32 `json:"preco_venda" xml:"preco_venda"`
 }
 
 // Uniao entre produto estrutural e comercial
 type UnionProduto struct {
 	Produto
-	PrecoVenda *float32 `json:"preco_venda"`
+	PrecoVenda *float32 `json:"preco_venda" xml:"preco_venda"`
 }
 
 type ProdutoCreate struct {
-	Nome string `json:"nome"`
-	Categoria string `json:"categoria"`
-	Marca string `json:"marca"`
+	Nome string `json:"nome" xml:"nome" validate:"required,max=50"`
+	Categoria string `json:"categoria" xml:"categoria"`
+	Marca string `json:"marca" xml:"marca"`
 }
 
 type ComercialCreate struct {
 	ProdutoCreate
-	PrecoVenda float32 `json:"preco_venda"`
+	PrecoVenda float32 `json:"preco_venda" xml:"preco_venda" validate:"gt=0"`
 }
 
 
@@ -47,7 +47,7 @@ func (cc ComercialCreate) ToComercial() Comercial {
 
 type ProdutoWithQnt struct {
 	Produto
-	Qnt uint64 `json:"quantidade_disponível"`
+	Qnt uint64 `json:"quantidade_disponível" xml:"quantidade_disponível"`
 }
 
 func (p *Produto) NewProdutoWithQnt(qnt uint64) ProdutoWithQnt {
@@ -1,52 +1,52 @@
 package model
 
 type SeriePonto struct {
-    Date     string  `json:"date"`
-    Receita  float64 `json:"receita"`
-    Despesa  float64 `json:"despesa"`
-    Lucro    float64 `json:"lucro"`
+    Date     string  `json:"date" xml:"date"`
+    Receita  float64 `json:"receita" xml:"receita"`
+    Despesa  float64 `json:"despesa" xml:"despesa"`
+    Lucro    float64 `json:"lucro" xml:"lucro"`
 }
 
 type RelatorioFinanceiro struct {
-    PeriodStart string       `json:"period_start"`
-    PeriodEnd   string       `json:"period_end"`
-    Granularity string       `json:"granularity"`
+    PeriodStart string       `json:"period_start" xml:"period_start"`
+    PeriodEnd   string       `json:"period_end" xml:"period_end"`
+    Granularity string       `json:"granularity" xml:"granularity"`
     Totals      struct {
-        Receita float64 `json:"receita"`
-        Despesa float64 `json:"despesa"`
-        Lucro   float64 `json:"lucro"`
-    } `json:"totals"`
-    Series     []SeriePonto `json:"series"`
-    Projection []SeriePonto `json:"projection,omitempty"`
+        Receita float64 `json:"receita" xml:"receita"`
+        Despesa float64 `json:"despesa" xml:"despesa"`
+        Lucro   float64 `json:"lucro" xml:"lucro"`
+    } `json:"totals" xml:"totals"`
+    Series     []SeriePonto `json:"series" xml:"series"`
+    Projection []SeriePonto `json:"projection,omitempty" xml:"projection,omitempty"`
 }
 
 type FuncionarioFolhaPagamento struct {
-    IdFuncionario   int64   `json:"id_funcionario"`
-    Nome            string  `json:"nome"`
-    CPF             string  `json:"cpf"`
-    Tipo            string  `json:"tipo"`
-    Expediente      string  `json:"expediente"`
-    SalarioBase     float64 `json:"salario_base"`
-    Bonificacao     float64 `json:"bonificacao"`
-    SalarioTotal    float64 `json:"salario_total"`
-    DataContratacao string  `json:"data_contratacao"`
+    IdFuncionario   int64   `json:"id_funcionario" xml:"id_funcionario"`
+    Nome            string  `json:"nome" xml:"nome"`
+    CPF             string  `json:"cpf" xml:"cpf"`
+    Tipo            TipoFuncionario `json:"tipo" xml:"tipo"`
+    Expediente      Expediente      `json:"expediente" xml:"expediente"`
+    SalarioBase     float64 `json:"salario_base" xml:"salario_base"`
+    Bonificacao     float64 `json:"bonificacao" xml:"bonificacao"`
+    SalarioTotal    float64 `json:"salario_total" xml:"salario_total"`
+    DataContratacao string  `json:"data_contratacao" xml:"data_contratacao"`
 }
 
 type FolhaPagamentoMensal struct {
-    Mes               string                      `json:"mes"`
-    Ano               int                         `json:"ano"`
-    TotalFuncionarios int                         `json:"total_funcionarios"`
-    TotalSalarioBase  float64                     `json:"total_salario_base"`
-    TotalBonificacoes float64                     `json:"total_bonificacoes"`
-    TotalFolha        float64                     `json:"total_folha"`
-    Funcionarios      []FuncionarioFolhaPagamento `json:"funcionarios"`
+    Mes               string                      `json:"mes" xml:"mes"`
+    Ano               int                         `json:"ano" xml:"ano"`
+    TotalFuncionarios int                         `json:"total_funcionarios" xml:"total_funcionarios"`
+    TotalSalarioBase  float64                     `json:"total_salario_base" xml:"total_salario_base"`
+    TotalBonificacoes float64                     `json:"total_bonificacoes" xml:"total_bonificacoes"`
+    TotalFolha        float64                     `json:"total_folha" xml:"total_folha"`
+    Funcionarios      []FuncionarioFolhaPagamento `json:"funcionarios" xml:"funcionarios"`
 }
 
 type RelatorioFolhaPagamento struct {
-    PeriodStart       string                 `json:"period_start"`
-    PeriodEnd         string                 `json:"period_end"`
-    TipoFiltro        string                 `json:"tipo_filtro,omitempty"`
-    TotalPeriodos     int                    `json:"total_periodos"`
-    TotalGeralFolha   float64                `json:"total_geral_folha"`
-    FolhasPorMes      []FolhaPagamentoMensal `json:"folhas_por_mes"`
+    PeriodStart       string                 `json:"period_start" xml:"period_start"`
+    PeriodEnd         string                 `json:"period_end" xml:"period_end"`
+    TipoFiltro        string                 `json:"tipo_filtro,omitempty" xml:"tipo_filtro,omitempty"`
+    TotalPeriodos     int                    `json:"total_periodos" xml:"total_periodos"`
+    TotalGeralFolha   float64                `json:"total_geral_folha" xml:"total_geral_folha"`
+    FolhasPorMes      []FolhaPagamentoMensal `json:"folhas_por_mes" xml:"folhas_por_mes"`
 }
@@ -5,20 +5,20 @@ import (
 )
 
 type Venda struct {
-	Id                int64     `json:"id"`
-	IdCliente         int64     `json:"id_cliente"`
-	IdFuncionario     int64     `json:"id_funcionario"`
-	DataHoraVenda     time.Time `json:"data_hora_renda"`
-	DataHoraPagamento *time.Time `json:"data_hora_pagamento"`
-	TipoPagamento     string    `json:"tipo_pagamento"`
+	Id                int64     `json:"id" xml:"id"`
+	IdCliente         int64     `json:"id_cliente" xml:"id_cliente"`
+	IdFuncionario     int64     `json:"id_funcionario" xml:"id_funcionario"`
+	DataHoraVenda     time.Time `json:"data_hora_renda" xml:"data_hora_renda"`
+	DataHoraPagamento *time.Time `json:"data_hora_pagamento" xml:"data_hora_pagamento"`
+	TipoPagamento     string    `json:"tipo_pagamento" xml:"tipo_pagamento"`
 }
 
 type VendaCreate struct {
-	IdCliente         int64     `json:"id_cliente"`
-	IdFuncionario     int64     `json:"id_funcionario"`
-	DataHoraVenda     time.Time `json:"data_hora_renda"`
-	DataHoraPagamento *time.Time `json:"data_hora_pagamento"`
-	TipoPagamento     string    `json:"tipo_pagamento"`
+	IdCliente         int64     `json:"id_cliente" xml:"id_cliente" validate:"required"`
+	IdFuncionario     int64     `json:"id_funcionario" xml:"id_funcionario" validate:"required"`
+	DataHoraVenda     time.Time `json:"data_hora_renda" xml:"data_hora_renda"`
+	DataHoraPagamento *time.Time `json:"data_hora_pagamento" xml:"data_hora_pagamento"`
+	TipoPagamento     string    `json:"tipo_pagamento" xml:"tipo_pagamento"`
 }
 
 func (vc *VendaCreate) ToVenda() Venda {
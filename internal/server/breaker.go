@@ -0,0 +1,43 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"edna/internal/util"
+)
+
+// Indica se a rota pode consultar o banco. Health check e rotas administrativas, de schema
+// e de validação respondem sem ele e não devem ser barradas por um banco indisponível.
+func usesDatabase(r *http.Request) bool {
+	path := r.URL.Path
+	switch {
+	case path == "/health", strings.HasPrefix(path, "/health/"), strings.HasPrefix(path, "/admin/"), strings.HasPrefix(path, "/schemas/"):
+		return false
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/validate"):
+		return false
+	}
+	return true
+}
+
+/// Middleware que responde 503 `database_unavailable` com `Retry-After` enquanto o circuit
+/// breaker do banco (s.breaker) estiver aberto, sem esperar o timeout das consultas. Com o
+/// circuito meio aberto só a requisição de teste passa; as demais recebem o mesmo 503.
+func (s *Server) breakerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !usesDatabase(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ok, retryAfter, done := s.breaker.Allow()
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			util.ErrorCodeJSON(w, util.DatabaseUnavailable, "Database unavailable, please try again later.", http.StatusServiceUnavailable)
+			return
+		}
+		defer done()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,11 +1,18 @@
 package server
 
 import (
+	"crypto/subtle"
+	"edna/internal/util"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Tempo (em segundos) sugerido aos clientes para tentar novamente durante a manutenção
+const maintenanceRetryAfter = 120
+
 type responseWriter struct {
 	statusCode int
 	http.ResponseWriter
@@ -18,9 +25,14 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
 		w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-CSRF-Token")
 		w.Header().Set("Access-Control-Allow-Credentials", "false") // Set to "true" if credentials are required
+		w.Header().Set("Access-Control-Expose-Headers", "Link, Warning, X-Total-Count")
 
-		// Handle preflight OPTIONS requests
-		if r.Method == http.MethodOptions {
+		// Handle preflight OPTIONS requests, other OPTIONS requests are answered by the router
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			// Permite ao navegador reaproveitar o preflight, evitando um OPTIONS por requisição
+			if s.corsMaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(s.corsMaxAge))
+			}
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
@@ -30,6 +42,40 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+/// Middleware que exige o token administrativo no header `Authorization: Bearer <token>`.
+/// Sem ADMIN_TOKEN configurado as rotas ficam desabilitadas e respondem 403.
+func (s *Server) adminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.adminToken == "" {
+			util.ErrorJSON(w, "Admin routes are disabled, set ADMIN_TOKEN to enable them.", http.StatusForbidden)
+			return
+		}
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			util.ErrorJSON(w, "Invalid or missing admin token.", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Prefixo da interface do swagger, que carrega scripts e estilos inline e não funciona com a CSP da API
+const swaggerPrefix = "/swagger/"
+
+// Adiciona os headers de segurança às respostas. A Content-Security-Policy não é
+// enviada para a interface do swagger.
+func (s *Server) securityHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+		if s.contentSecurityPolicy != "" && !strings.HasPrefix(r.URL.Path, swaggerPrefix) {
+			w.Header().Set("Content-Security-Policy", s.contentSecurityPolicy)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 /// Middleware para logar as requisições saindo
 func (s *Server) logMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request){
@@ -39,6 +85,9 @@ func (s *Server) logMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(&res, r)
 
+		if s.logSampler != nil && !s.logSampler.sample(res.statusCode) {
+			return
+		}
 		log.Printf("[%s] %s %d %s in %s", r.Method, r.URL.Path, res.statusCode, http.StatusText(res.statusCode), time.Since(now))
 	})
 }
@@ -47,3 +96,116 @@ func (w *responseWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 	w.ResponseWriter.WriteHeader(statusCode)
 }
+
+// Permite ao http.ResponseController acessar o writer original (ex: para Flush)
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// ResponseWriter que guarda apenas o status e os headers, descartando o corpo
+type headerRecorder struct {
+	header     http.Header
+	statusCode int
+}
+
+func (w *headerRecorder) Header() http.Header {
+	return w.header
+}
+
+func (w *headerRecorder) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *headerRecorder) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
+
+/// Middleware que responde 503 para todas as rotas enquanto o modo de manutenção
+/// estiver ativo. Health check e rotas administrativas continuam acessíveis.
+func (s *Server) maintenanceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.maintenance.Load() || r.URL.Path == "/health" || strings.HasPrefix(r.URL.Path, "/health/") || strings.HasPrefix(r.URL.Path, "/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Retry-After", strconv.Itoa(maintenanceRetryAfter))
+		util.ErrorJSON(w, "Service under maintenance, please try again later.", http.StatusServiceUnavailable)
+	})
+}
+
+/// Middleware que indenta as respostas JSON quando a requisição pede `?pretty=true`.
+/// O padrão continua sendo o JSON compacto.
+func (s *Server) prettyJSONMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pretty, _ := strconv.ParseBool(r.URL.Query().Get("pretty")); pretty {
+			w = util.WithPrettyJSON(w)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+/// Middleware de negociação de conteúdo das rotas de leitura (GET): responde em XML
+/// quando o header `Accept` pede `application/xml` e 406 quando nenhum tipo aceito
+/// é suportado. As demais requisições e a ausência do header continuam em JSON.
+/// Rotas de `mux` registradas com util.Produces também aceitam os tipos que declaram.
+func (s *Server) negotiateMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept")
+		accept := r.Header.Get("Accept")
+		format, ok := util.NegotiateFormat(accept)
+		if !ok {
+			// O próprio handler escolhe o formato, ex: application/x-ndjson
+			if h, _ := mux.Handler(r); util.AcceptsMediaType(accept, util.MediaTypes(h)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			util.ErrorJSON(w, "Not acceptable, supported types are application/json and application/xml.", http.StatusNotAcceptable)
+			return
+		}
+		if format == util.FormatXML {
+			w = util.WithXML(w)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+/// Middleware que normaliza a barra final dos caminhos abaixo de `root` redirecionando (308)
+/// para a forma canônica: sem barra por padrão, com barra se preferTrailingSlash estiver ativo.
+/// As rotas são registradas sem barra, então na forma com barra ela é removida antes do roteamento.
+func (s *Server) trailingSlashMiddleware(root string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if path == root || !strings.HasPrefix(path, root) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		trimmed := strings.TrimRight(path, "/")
+		canonical := trimmed
+		if s.preferTrailingSlash {
+			canonical += "/"
+		}
+		if path != canonical {
+			target := *r.URL
+			target.Path, target.RawPath = canonical, ""
+			http.Redirect(w, r, target.RequestURI(), http.StatusPermanentRedirect)
+			return
+		}
+
+		if s.preferTrailingSlash {
+			u := *r.URL
+			u.Path, u.RawPath = trimmed, ""
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = &u
+			r = r2
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"edna/internal/util"
+)
+
+// Tempo (em segundos) sugerido aos clientes para tentar novamente com o pool esgotado
+const poolRetryAfter = 1
+
+// Indica se o pool tem uma conexão livre (ou pode abrir uma) dentro de `wait`. A conexão
+// obtida é devolvida ao pool em seguida. Só o prazo de espera esgotado conta como pool
+// cheio: outras falhas (banco fora do ar, cliente desconectado) ficam para o handler e
+// para o circuit breaker.
+func poolAvailable(ctx context.Context, db *sql.DB, wait time.Duration) bool {
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	conn, err := db.Conn(waitCtx)
+	if err != nil {
+		return !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil
+	}
+	conn.Close()
+	return true
+}
+
+/// Middleware que responde 503 com `Retry-After` quando o pool de conexões do banco
+/// está esgotado, em vez de deixar as consultas esperando uma conexão até o timeout da
+/// requisição e terminar em um 500 genérico. A espera é medida no próprio *sql.DB, então
+/// rotas que não usam o banco não são afetadas e exportações longas só ocupam a conexão
+/// que de fato usam. Pool cheio não conta como falha para o circuit breaker.
+func (s *Server) poolMiddleware(next http.Handler) http.Handler {
+	if s.poolWait <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !usesDatabase(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if db := s.db.Conn(); db != nil && !poolAvailable(r.Context(), db, s.poolWait) {
+			w.Header().Set("Retry-After", strconv.Itoa(poolRetryAfter))
+			util.ErrorJSON(w, "Database is busy, please try again later.", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"edna/internal/util"
+)
+
+// Limitador de requisições por cliente em janelas fixas de tempo
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	clients map[string]*clientWindow
+}
+
+type clientWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*clientWindow),
+	}
+}
+
+// Registra uma requisição do cliente. Retorna false e o tempo até a próxima
+// janela caso o limite tenha sido atingido.
+func (rl *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	cw, ok := rl.clients[client]
+	if !ok || now.Sub(cw.start) >= rl.window {
+		rl.purge(now)
+		rl.clients[client] = &clientWindow{start: now, count: 1}
+		return true, 0
+	}
+
+	if cw.count >= rl.limit {
+		return false, cw.start.Add(rl.window).Sub(now)
+	}
+	cw.count++
+	return true, 0
+}
+
+// Remove janelas expiradas para o mapa não crescer indefinidamente
+func (rl *rateLimiter) purge(now time.Time) {
+	for client, cw := range rl.clients {
+		if now.Sub(cw.start) >= rl.window {
+			delete(rl.clients, client)
+		}
+	}
+}
+
+// Identifica o cliente pelo endereço da conexão. O header X-Real-IP só é usado quando a
+// conexão vem de um proxy confiável (TRUSTED_PROXIES), caso contrário qualquer cliente
+// poderia escolher o próprio IP e escapar do limite.
+func (s *Server) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := r.Header.Get("X-Real-IP"); ip != "" && s.trustedProxy(host) {
+		return ip
+	}
+	return host
+}
+
+func (s *Server) trustedProxy(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	return slices.ContainsFunc(s.trustedProxies, func(p netip.Prefix) bool { return p.Contains(addr) })
+}
+
+/// Middleware que limita a quantidade de requisições por cliente, respondendo 429 quando excedida
+func (s *Server) rateLimitMiddleware(rl *rateLimiter, next http.Handler) http.Handler {
+	if rl == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, retryAfter := rl.allow(s.clientIP(r), time.Now())
+		if !ok {
+			seconds := int(retryAfter.Seconds()) + 1
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			util.ErrorJSON(w, "Too many requests, please try again later.", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"edna/internal/config"
 	"edna/internal/services/aplica_oferta"
 	"edna/internal/services/cliente"
 	"edna/internal/services/fornecedor"
@@ -12,7 +13,7 @@ import (
 	"edna/internal/services/produto"
 	"edna/internal/services/relatorio"
 	"edna/internal/services/venda"
-	"encoding/json"
+	"edna/internal/util"
 	"log"
 	"net/http"
 
@@ -21,10 +22,17 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+type maintenanceStatus struct {
+	Enabled bool `json:"enabled" xml:"enabled"`
+}
+
 func (s *Server) RegisterRoutes() http.Handler {
 
 	v1 := http.NewServeMux()
 	mux := http.NewServeMux()
+	// Toda rota da API registrada por `api` é anotada em s.routes e listada em /admin/routes
+	s.routes = &util.RouteTable{}
+	api := s.routes.Record(mux, "/v1")
 
 	itemVendaHandler := item_venda.NewHandler(s.itemVendaStore)
 	fornecedorHandler := fornecedor.NewHandler(s.fornecedorStore)
@@ -38,62 +46,170 @@ func (s *Server) RegisterRoutes() http.Handler {
 	itemOfertaHandler := item_oferta.NewHandler(s.itemOfertaStore)
 	aplicaOfertaHandler := aplica_oferta.NewHandler(s.aplicaOfertaStore)
 
-	mux.HandleFunc("/health", s.healthHandler)
-	fornecedorHandler.RegisterRoutes(mux)
-	produtoHandler.RegisterRoutes(mux)
-	clienteHandler.RegisterRoutes(mux)
-	loteHandler.RegisterRoutes(mux)
-	ofertaHandler.RegisterRoutes(mux)
-	vendaHandler.RegisterRoutes(mux)
-	relatorioHandler.RegisterRoutes(mux)
-	funcionarioHandler.RegisterRoutes(mux)
-	itemVendaHandler.RegisterRoutes(mux)
-	itemOfertaHandler.RegisterRoutes(mux)
-	aplicaOfertaHandler.RegisterRoutes(mux)
+	api.HandleFunc("/health", s.healthHandler)
+	// Rotas administrativas alteram o estado do servidor, expõem a configuração ou consultam
+	// o banco sob demanda (self-test), exigem ADMIN_TOKEN
+	adminMux := http.NewServeMux()
+	admin := s.routes.Record(adminMux, "/v1")
+	admin.HandleFunc("GET /health/selftest", s.selfTestHandler)
+	admin.HandleFunc("GET /admin/maintenance", s.getMaintenanceHandler)
+	admin.HandleFunc("PUT /admin/maintenance", s.setMaintenanceHandler)
+	admin.HandleFunc("GET /admin/features", s.featuresHandler)
+	admin.HandleFunc("GET /admin/routes", s.routesHandler)
+	adminHandler := s.adminMiddleware(s.jsonFallback(adminMux))
+	mux.Handle("/admin/", adminHandler)
+	mux.Handle("/health/selftest", adminHandler)
+	api.HandleFunc("GET /schemas/{entity}", s.schemaHandler)
+	for entity, path := range collectionPaths {
+		api.HandleFunc("POST "+path+"/validate", s.validateHandler(entity))
+	}
+	fornecedorHandler.RegisterRoutes(api)
+	produtoHandler.RegisterRoutes(api)
+	clienteHandler.RegisterRoutes(api)
+	loteHandler.RegisterRoutes(api)
+	ofertaHandler.RegisterRoutes(api)
+	vendaHandler.RegisterRoutes(api)
+	// Relatórios são consultas caras, por isso têm um limite de requisições próprio
+	relatorioMux := http.NewServeMux()
+	relatorioHandler.RegisterRoutes(s.routes.Record(relatorioMux, "/v1"))
+	mux.Handle("/relatorios/", s.rateLimitMiddleware(s.relatorioLimiter, s.jsonFallback(relatorioMux)))
+	funcionarioHandler.RegisterRoutes(api)
+	itemVendaHandler.RegisterRoutes(api)
+	itemOfertaHandler.RegisterRoutes(api)
+	aplicaOfertaHandler.RegisterRoutes(api)
 
 	// Register routes
 	v1.HandleFunc("/", s.trailingSlashHandler)
-	v1.Handle("/v1/", http.StripPrefix("/v1", mux))
-	v1.Handle("/swagger/", httpSwagger.Handler())
+	v1.Handle("/v1/", s.trailingSlashMiddleware("/v1/", http.StripPrefix("/v1", s.prettyJSONMiddleware(s.negotiateMiddleware(mux, s.maintenanceMiddleware(s.breakerMiddleware(s.poolMiddleware(s.jsonFallback(mux)))))))))
+	v1.Handle(swaggerPrefix, httpSwagger.Handler())
 	// Wrap the mux with CORS middleware
-	return s.logMiddleware(s.corsMiddleware(v1))
+	handler := s.corsMiddleware(v1)
+	if s.features.Enabled(config.FeatureSecurityHeaders) {
+		handler = s.securityHeadersMiddleware(handler)
+	}
+	if s.features.Enabled(config.FeatureRequestLog) {
+		handler = s.logMiddleware(handler)
+	}
+	return handler
+}
+
+// Substitui as respostas 404 e 405 em texto puro do ServeMux por respostas em JSON,
+// mantendo o header `Allow` das respostas 405. Requisições OPTIONS a um recurso
+// existente respondem 204 com os métodos registrados para ele no header `Allow`.
+func (s *Server) jsonFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		// Deixa o ServeMux decidir entre 404 e 405, descartando o corpo
+		rec := &headerRecorder{header: http.Header{}, statusCode: http.StatusNotFound}
+		mux.ServeHTTP(rec, r)
+
+		if rec.statusCode == http.StatusMethodNotAllowed && r.Method == http.MethodOptions {
+			w.Header().Set("Allow", rec.header.Get("Allow")+", OPTIONS")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if rec.statusCode == http.StatusMethodNotAllowed {
+			w.Header().Set("Allow", rec.header.Get("Allow"))
+			util.ErrorJSON(w, "Method not allowed.", http.StatusMethodNotAllowed)
+			return
+		}
+		util.ErrorJSON(w, "Not found.", http.StatusNotFound)
+	})
 }
 
 // @Summary Unmatched path handler
 // @Description Returns a 404 JSON response for unmatched routes.
 // @Tags Server
 // @Produce json
-// @Success 404 {object} map[string]string
+// @Failure 404 {object} types.ErrorResponse
 // @Router / [get]
 func (s *Server) trailingSlashHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotFound)
-	resp := map[string]string{"message": "Unmatched path, please check your url path and try again."}
-	jsonResp, err := json.Marshal(resp)
-	if err != nil {
-		http.Error(w, "Failed to marshal response", http.StatusInternalServerError)
-		return
-	}
-	if _, err := w.Write(jsonResp); err != nil {
-		log.Printf("Failed to write response: %v", err)
-	}
+	util.ErrorJSON(w, "Unmatched path, please check your url path and try again.", http.StatusNotFound)
 }
 
 // @Summary Check health of the system
-// @Description Returns the health status of the application and dependencies.
+// @Description Returns the health status of the application and dependencies. Responds 503 when the database is down.
 // @Tags Server
 // @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} map[string]string
+// @Success 200 {object} database.HealthStats
+// @Failure 503 {object} database.HealthStats
 // @Router /health [get]
 func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
-	resp, err := json.Marshal(s.db.Health())
-	if err != nil {
-		http.Error(w, "Failed to marshal health check response", http.StatusInternalServerError)
-		return
+	health := s.db.Health()
+	health.Breaker = s.breaker.State()
+	// Banco fora do ar responde 503 para que balanceadores e orquestradores tirem a instância de rotação
+	status := http.StatusOK
+	if health.Status == "down" {
+		status = http.StatusServiceUnavailable
 	}
-	w.Header().Set("Content-Type", "application/json")
-	if _, err := w.Write(resp); err != nil {
+	if err := util.WriteJSON(w, status, health); err != nil {
 		log.Printf("Failed to write response: %v", err)
 	}
 }
+
+// @Summary List API routes
+// @Description Returns every registered method and path of the API. An empty method accepts any method.
+// @Tags Server
+// @Produce json
+// @Success 200 {array} util.Route
+// @Param Authorization header string true "Bearer <ADMIN_TOKEN>"
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Router /admin/routes [get]
+func (s *Server) routesHandler(w http.ResponseWriter, r *http.Request) {
+	util.WriteJSON(w, http.StatusOK, s.routes.Routes())
+}
+
+// @Summary List feature flags
+// @Description Returns every optional feature and whether it is enabled in this deployment (FEATURE_<NAME>=on|off).
+// @Tags Server
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Param Authorization header string true "Bearer <ADMIN_TOKEN>"
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Router /admin/features [get]
+func (s *Server) featuresHandler(w http.ResponseWriter, r *http.Request) {
+	util.WriteJSON(w, http.StatusOK, s.features.All())
+}
+
+// @Summary Get maintenance mode
+// @Description Returns whether the maintenance mode is enabled.
+// @Tags Server
+// @Produce json
+// @Success 200 {object} maintenanceStatus
+// @Param Authorization header string true "Bearer <ADMIN_TOKEN>"
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Router /admin/maintenance [get]
+func (s *Server) getMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	util.WriteJSON(w, http.StatusOK, maintenanceStatus{Enabled: s.maintenance.Load()})
+}
+
+// @Summary Toggle maintenance mode
+// @Description Enables or disables the maintenance mode at runtime. While enabled every route but health, admin and docs answers with 503.
+// @Tags Server
+// @Accept json
+// @Produce json
+// @Param status body maintenanceStatus true "Maintenance status"
+// @Success 200 {object} maintenanceStatus
+// @Failure 400 {object} types.ErrorResponse
+// @Param Authorization header string true "Bearer <ADMIN_TOKEN>"
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Router /admin/maintenance [put]
+func (s *Server) setMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	var payload maintenanceStatus
+	if err := util.ReadJSON(r, &payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.maintenance.Store(payload.Enabled)
+	log.Printf("Maintenance mode set to %t", payload.Enabled)
+	util.WriteJSON(w, http.StatusOK, payload)
+}
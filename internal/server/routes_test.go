@@ -1,10 +1,25 @@
 package server
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"edna/docs"
+	"edna/internal/config"
+	"edna/internal/database"
+	"edna/internal/types"
+	"edna/internal/util"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestHandler(t *testing.T) {
@@ -20,12 +35,807 @@ func TestHandler(t *testing.T) {
 	if resp.StatusCode != http.StatusNotFound {
 		t.Errorf("expected status OK; got %v", resp.Status)
 	}
-	expected := "{\"message\":\"Unmatched path, please check your url path and try again.\"}"
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		t.Fatalf("error reading response body. Err: %v", err)
 	}
-	if expected != string(body) {
-		t.Errorf("expected response body to be %v; got %v", expected, string(body))
+	assertErrorBody(t, body, "not_found", "Unmatched path, please check your url path and try again.")
+}
+
+// Verifica se o corpo segue o schema types.ErrorResponse, com exatamente os campos
+// error, detail e timestamp
+func assertErrorBody(t *testing.T, body []byte, code, detail string) {
+	t.Helper()
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("expected a JSON error body; got %q (%v)", body, err)
+	}
+	if len(fields) != 3 {
+		t.Errorf("expected only error, detail and timestamp; got %v", fields)
+	}
+	if fields["error"] != code {
+		t.Errorf("expected error code %q; got %v", code, fields["error"])
+	}
+	if fields["detail"] != detail {
+		t.Errorf("expected detail %q; got %v", detail, fields["detail"])
+	}
+	timestamp, _ := fields["timestamp"].(string)
+	if _, err := time.Parse(time.RFC3339, timestamp); err != nil {
+		t.Errorf("expected an RFC 3339 timestamp; got %v", fields["timestamp"])
+	}
+}
+
+// O corpo é comparado byte a byte: os campos saem na ordem da struct, não em ordem
+// alfabética como aconteceria com um map
+func TestHealthHandlerIsByteStable(t *testing.T) {
+	s := &Server{
+		db: stubDB{health: database.HealthStats{
+			Status:          "up",
+			Message:         "It's healthy",
+			OpenConnections: "2",
+			InUse:           "1",
+			Idle:            "1",
+		}},
+		breaker: util.NewCircuitBreaker(3, time.Minute),
+	}
+	const golden = `{"status":"up","message":"It's healthy","open_connections":"2","in_use":"1","idle":"1","breaker":"closed"}`
+	rec := httptest.NewRecorder()
+	s.healthHandler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if body := strings.TrimSpace(rec.Body.String()); body != golden {
+		t.Errorf("expected body %s; got %s", golden, body)
+	}
+}
+
+const testAdminToken = "segredo"
+
+// Requisição a uma rota /admin autenticada com testAdminToken
+func adminRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	return req
+}
+
+type stubDB struct {
+	health database.HealthStats
+	conn   *sql.DB
+}
+
+func (s stubDB) Health() database.HealthStats { return s.health }
+func (s stubDB) Conn() *sql.DB                { return s.conn }
+func (s stubDB) Close() error                 { return nil }
+
+func TestHealthHandlerEscapesValues(t *testing.T) {
+	s := &Server{db: stubDB{health: database.HealthStats{
+		Status: "down",
+		Error:  `db down: relation "produto" does not exist`,
+	}}}
+	rec := httptest.NewRecorder()
+	s.healthHandler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var got database.HealthStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("expected valid JSON; got %q (%v)", rec.Body.String(), err)
+	}
+	if got.Error != `db down: relation "produto" does not exist` {
+		t.Errorf("expected error to round-trip; got %q", got.Error)
+	}
+}
+
+func TestHealthHandlerStatusCode(t *testing.T) {
+	for status, want := range map[string]int{
+		"up":       http.StatusOK,
+		"degraded": http.StatusOK,
+		"down":     http.StatusServiceUnavailable,
+	} {
+		s := &Server{db: stubDB{health: database.HealthStats{Status: status}}}
+		rec := httptest.NewRecorder()
+		s.healthHandler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		if rec.Code != want {
+			t.Errorf("expected status %d for a %q database; got %d", want, status, rec.Code)
+		}
+	}
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	s := &Server{db: stubDB{health: database.HealthStats{Status: "up"}}, adminToken: testAdminToken}
+	s.maintenance.Store(true)
+	handler := s.RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/produtos", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 for data route; got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header to be set")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 for /health; got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	body := strings.NewReader(`{"enabled": false}`)
+	handler.ServeHTTP(rec, adminRequest(http.MethodPut, "/v1/admin/maintenance", body))
+	if rec.Code != http.StatusOK || s.maintenance.Load() {
+		t.Errorf("expected maintenance to be disabled; got status %d", rec.Code)
+	}
+}
+
+func TestAdminRoutesRequireToken(t *testing.T) {
+	s := &Server{db: stubDB{}, adminToken: testAdminToken}
+	handler := s.RegisterRoutes()
+
+	for _, auth := range []string{"", "Bearer errado", testAdminToken} {
+		req := httptest.NewRequest(http.MethodPut, "/v1/admin/maintenance", strings.NewReader(`{"enabled": true}`))
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401 with Authorization %q; got %d", auth, rec.Code)
+		}
+	}
+	if s.maintenance.Load() {
+		t.Error("expected unauthenticated requests not to enable maintenance")
+	}
+
+	// O self-test roda consultas reais no banco, também exige o token
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health/selftest", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 for the self-test without a token; got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, adminRequest(http.MethodGet, "/v1/admin/features", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 with the admin token; got %d", rec.Code)
+	}
+
+	// Sem ADMIN_TOKEN as rotas ficam desabilitadas, mesmo para quem envia um token
+	handler = (&Server{db: stubDB{}}).RegisterRoutes()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, adminRequest(http.MethodGet, "/v1/admin/routes", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 without ADMIN_TOKEN; got %d", rec.Code)
+	}
+}
+
+func TestUnmatchedRoutesReturnJSON(t *testing.T) {
+	s := &Server{db: stubDB{}}
+	handler := s.RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/inexistente", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404; got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type; got %q", ct)
+	}
+	assertErrorBody(t, rec.Body.Bytes(), "not_found", "Not found.")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/v1/fornecedores", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405; got %d", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); !strings.Contains(allow, "GET") || !strings.Contains(allow, "POST") {
+		t.Errorf("expected Allow header to list GET and POST; got %q", allow)
+	}
+	assertErrorBody(t, rec.Body.Bytes(), "method_not_allowed", "Method not allowed.")
+}
+
+func TestOptionsListsAllowedMethods(t *testing.T) {
+	s := &Server{db: stubDB{}}
+	handler := s.RegisterRoutes()
+
+	tests := []struct {
+		path  string
+		allow string
+	}{
+		{"/v1/fornecedores", "GET, HEAD, POST, OPTIONS"},
+		{"/v1/fornecedores/1", "DELETE, GET, HEAD, PUT, OPTIONS"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, tt.path, nil))
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("%s: expected status 204; got %d", tt.path, rec.Code)
+		}
+		if allow := rec.Header().Get("Allow"); allow != tt.allow {
+			t.Errorf("%s: expected Allow %q; got %q", tt.path, tt.allow, allow)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/v1/inexistente", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown resource; got %d", rec.Code)
+	}
+}
+
+func TestPreflightIsAnsweredByCORS(t *testing.T) {
+	s := &Server{db: stubDB{}}
+	handler := s.RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/inexistente", nil)
+	req.Header.Set("Origin", "http://localhost:5173")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected status 204 for preflight; got %d", rec.Code)
+	}
+}
+
+func TestPreflightMaxAge(t *testing.T) {
+	s := &Server{db: stubDB{}, corsMaxAge: 900}
+	handler := s.RegisterRoutes()
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/produtos", nil)
+	req.Header.Set("Origin", "http://localhost:5173")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "900" {
+		t.Errorf("expected Access-Control-Max-Age 900 on preflight; got %q", got)
+	}
+
+	// Requisições comuns não levam o header
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "" {
+		t.Errorf("expected no Access-Control-Max-Age outside preflight; got %q", got)
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	csp := "default-src 'none'"
+	s := &Server{db: stubDB{}, contentSecurityPolicy: csp}
+	handler := s.RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	expected := map[string]string{
+		"X-Content-Type-Options":  "nosniff",
+		"X-Frame-Options":         "DENY",
+		"Content-Security-Policy": csp,
+	}
+	for header, value := range expected {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("expected %s %q on API responses; got %q", header, value, got)
+		}
+	}
+
+	// A interface do swagger depende de scripts inline, então fica sem CSP
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+	if got := rec.Header().Get("Content-Security-Policy"); got != "" {
+		t.Errorf("expected no Content-Security-Policy on the swagger UI; got %q", got)
+	}
+	if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("expected nosniff on the swagger UI; got %q", got)
+	}
+
+	// Desligar a funcionalidade remove todos os headers
+	s = &Server{db: stubDB{}, contentSecurityPolicy: csp, features: config.Features{config.FeatureSecurityHeaders: false}}
+	rec = httptest.NewRecorder()
+	s.RegisterRoutes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	for header := range expected {
+		if got := rec.Header().Get(header); got != "" {
+			t.Errorf("expected no %s with security headers disabled; got %q", header, got)
+		}
+	}
+}
+
+func TestBreakerOpen(t *testing.T) {
+	breaker := util.NewCircuitBreaker(1, time.Minute)
+	breaker.Record(driver.ErrBadConn)
+	handler := (&Server{db: stubDB{health: database.HealthStats{Status: "up"}}, breaker: breaker}).RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/produtos", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503 with the breaker open; got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "60" {
+		t.Errorf("expected Retry-After with the remaining cooldown; got %q", rec.Header().Get("Retry-After"))
+	}
+	var body types.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Error != "database_unavailable" {
+		t.Errorf("expected error code database_unavailable; got %+v (%v)", body, err)
+	}
+
+	// Rotas que não usam o banco continuam respondendo, e o /health mostra o estado do breaker
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/schemas/cliente", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 for a schema; got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	var health database.HealthStats
+	if err := json.NewDecoder(rec.Body).Decode(&health); err != nil {
+		t.Fatalf("error decoding health. Err: %v", err)
+	}
+	if health.Breaker != util.BreakerOpen {
+		t.Errorf("expected /health to report the breaker as open; got %q", health.Breaker)
+	}
+}
+
+func TestRelatorioRateLimit(t *testing.T) {
+	s := &Server{db: stubDB{}, relatorioLimiter: newRateLimiter(2, time.Minute)}
+	handler := s.RegisterRoutes()
+
+	for i := range 2 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/relatorios/financeiro", nil))
+		if rec.Code == http.StatusTooManyRequests {
+			t.Fatalf("request %d: expected to be within the limit", i+1)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/relatorios/financeiro", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status 429 after exceeding the limit; got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header to be set")
+	}
+
+	for range 5 {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected other routes to be unaffected; got %d", rec.Code)
+		}
+	}
+}
+
+func TestClientIPTrustsOnlyConfiguredProxies(t *testing.T) {
+	s := &Server{trustedProxies: []netip.Prefix{netip.MustParsePrefix("172.16.0.0/12")}}
+	for _, tc := range []struct {
+		remoteAddr string
+		realIP     string
+		want       string
+	}{
+		{"172.18.0.5:41000", "203.0.113.7", "203.0.113.7"},
+		{"172.18.0.5:41000", "", "172.18.0.5"},
+		{"198.51.100.2:41000", "203.0.113.7", "198.51.100.2"},
+		{"[::ffff:172.18.0.5]:41000", "203.0.113.7", "203.0.113.7"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/relatorios/financeiro", nil)
+		req.RemoteAddr = tc.remoteAddr
+		if tc.realIP != "" {
+			req.Header.Set("X-Real-IP", tc.realIP)
+		}
+		if got := s.clientIP(req); got != tc.want {
+			t.Errorf("remote %s with X-Real-IP %q: expected client %s; got %s", tc.remoteAddr, tc.realIP, tc.want, got)
+		}
+	}
+
+	// Sem proxies confiáveis o header é sempre ignorado
+	req := httptest.NewRequest(http.MethodGet, "/relatorios/financeiro", nil)
+	req.Header.Set("X-Real-IP", "203.0.113.7")
+	if got := (&Server{}).clientIP(req); got != "192.0.2.1" {
+		t.Errorf("expected the connection address; got %s", got)
+	}
+}
+
+func TestSchemaHandler(t *testing.T) {
+	handler := (&Server{db: stubDB{}}).RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/schemas/fornecedor", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rec.Code)
+	}
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Type      any `json:"type"`
+			MaxLength int `json:"maxLength"`
+		} `json:"properties"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&schema); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "nome" {
+		t.Errorf("expected only nome to be required; got %v", schema.Required)
+	}
+	if nome := schema.Properties["nome"]; nome.Type != "string" || nome.MaxLength != 50 {
+		t.Errorf("unexpected schema for nome: %+v", nome)
+	}
+	if _, ok := schema.Properties["cnpj"]; !ok {
+		t.Error("expected cnpj to be described")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/schemas/livro", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown entity; got %d", rec.Code)
+	}
+}
+
+func TestValidateHandler(t *testing.T) {
+	handler := (&Server{db: stubDB{}}).RegisterRoutes()
+
+	validate := func(path, body string) util.ValidationResponse {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 for %s; got %d", path, rec.Code)
+		}
+		var res util.ValidationResponse
+		if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+			t.Fatalf("error decoding response body. Err: %v", err)
+		}
+		return res
+	}
+
+	if res := validate("/v1/fornecedores/validate", `{"nome": "Ambev", "cnpj": "12345678000190"}`); !res.Valid || len(res.Errors) != 0 {
+		t.Errorf("expected a valid fornecedor; got %+v", res)
+	}
+
+	res := validate("/v1/fornecedores/validate", `{"nome": " ", "cnpj": "123456780001900000"}`)
+	if res.Valid {
+		t.Fatal("expected an invalid fornecedor")
+	}
+	fields := map[string]bool{}
+	for _, fe := range res.Errors {
+		fields[fe.Field] = true
+	}
+	if !fields["nome"] || !fields["cnpj"] || len(res.Errors) != 2 {
+		t.Errorf("expected errors for nome and cnpj; got %+v", res.Errors)
+	}
+
+	// Regras de negócio do modelo também são verificadas
+	res = validate("/v1/funcionarios/validate", `{"nome": "Ana", "CPF": "12345678901", "tipo": "gerente", "expediente": "noite", "data_contratacao": "2024-01-01"}`)
+	if res.Valid || len(res.Errors) != 1 || !strings.Contains(res.Errors[0].Message, "gerente") {
+		t.Errorf("expected the tipo to be rejected; got %+v", res)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/clientes/validate", strings.NewReader("{")))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a malformed body; got %d", rec.Code)
+	}
+}
+
+// Driver que só abre conexões, suficiente para disputar o pool de um *sql.DB
+type poolConnector struct{}
+
+func (poolConnector) Connect(context.Context) (driver.Conn, error) { return poolConn{}, nil }
+func (poolConnector) Driver() driver.Driver                        { return nil }
+
+type poolConn struct{}
+
+func (poolConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not implemented") }
+func (poolConn) Close() error                        { return nil }
+func (poolConn) Begin() (driver.Tx, error)           { return nil, errors.New("not implemented") }
+
+func TestPoolExhausted(t *testing.T) {
+	db := sql.OpenDB(poolConnector{})
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	s := &Server{db: stubDB{conn: db}, poolWait: 50 * time.Millisecond}
+	handler := s.poolMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Outra requisição ocupa a única conexão do pool
+	held, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clientes", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 with the pool exhausted; got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header to be set")
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/health", nil),
+		httptest.NewRequest(http.MethodGet, "/schemas/cliente", nil),
+		httptest.NewRequest(http.MethodPost, "/clientes/validate", nil),
+	} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected %s %s to bypass the pool; got %d", req.Method, req.URL.Path, rec.Code)
+		}
+	}
+
+	held.Close()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clientes", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 once the connection is released; got %d", rec.Code)
+	}
+	if stats := db.Stats(); stats.InUse != 0 {
+		t.Errorf("expected the probed connection to be returned to the pool; %d in use", stats.InUse)
+	}
+}
+
+type stubCounter struct {
+	rows int64
+	err  error
+}
+
+func (s stubCounter) Count(ctx context.Context, filter *util.Filter) (int64, error) {
+	return s.rows, s.err
+}
+
+func TestSelfTestHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		store  stubCounter
+		code   int
+		status string
+	}{
+		{"working database", stubCounter{rows: 42}, http.StatusOK, "up"},
+		{"failing database", stubCounter{err: errors.New("connection refused")}, http.StatusServiceUnavailable, "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := (&Server{db: stubDB{}, selfTestStore: tt.store, adminToken: testAdminToken}).RegisterRoutes()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, adminRequest(http.MethodGet, "/v1/health/selftest", nil))
+			if rec.Code != tt.code {
+				t.Fatalf("expected status %d; got %d", tt.code, rec.Code)
+			}
+			var result selfTestResult
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("error decoding response body. Err: %v", err)
+			}
+			if result.Status != tt.status {
+				t.Errorf("expected status %q; got %q", tt.status, result.Status)
+			}
+			if result.Rows != tt.store.rows {
+				t.Errorf("expected %d rows; got %d", tt.store.rows, result.Rows)
+			}
+			if tt.store.err != nil && result.Error != tt.store.err.Error() {
+				t.Errorf("expected error %q; got %q", tt.store.err, result.Error)
+			}
+		})
+	}
+}
+
+func TestTrailingSlashRedirect(t *testing.T) {
+	tests := []struct {
+		prefer   bool
+		path     string
+		location string
+	}{
+		{false, "/v1/health/?full=1", "/v1/health?full=1"},
+		{false, "/v1/fornecedores/1/", "/v1/fornecedores/1"},
+		{true, "/v1/health?full=1", "/v1/health/?full=1"},
+	}
+	for _, tt := range tests {
+		handler := (&Server{db: stubDB{}, preferTrailingSlash: tt.prefer}).RegisterRoutes()
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != http.StatusPermanentRedirect {
+			t.Errorf("%s: expected status 308; got %d", tt.path, rec.Code)
+		}
+		if loc := rec.Header().Get("Location"); loc != tt.location {
+			t.Errorf("%s: expected location %q; got %q", tt.path, tt.location, loc)
+		}
+	}
+
+	// A forma canônica chega ao mesmo handler nos dois modos
+	for _, prefer := range []bool{false, true} {
+		handler := (&Server{db: stubDB{}, preferTrailingSlash: prefer}).RegisterRoutes()
+		path := "/v1/health"
+		if prefer {
+			path += "/"
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected status 200; got %d", path, rec.Code)
+		}
+	}
+}
+
+func TestCreateWithEmptyBody(t *testing.T) {
+	handler := (&Server{db: stubDB{}}).RegisterRoutes()
+	paths := []string{
+		"/v1/fornecedores", "/v1/clientes", "/v1/funcionarios", "/v1/lotes",
+		"/v1/produtos", "/v1/produtos/comercial", "/v1/ofertas", "/v1/vendas",
+		"/v1/item_venda", "/v1/item_ofertas", "/v1/aplica_oferta",
+	}
+	for _, path := range paths {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader("")))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400; got %d", path, rec.Code)
+		}
+		assertErrorBody(t, rec.Body.Bytes(), "bad_request", "Request body is required")
+	}
+}
+
+func TestRouteTable(t *testing.T) {
+	handler := (&Server{db: stubDB{}, adminToken: testAdminToken}).RegisterRoutes()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, adminRequest(http.MethodGet, "/v1/admin/routes", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rec.Code)
+	}
+	var routes []util.Route
+	if err := json.NewDecoder(rec.Body).Decode(&routes); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+
+	registered := make(map[util.Route]bool, len(routes))
+	for _, route := range routes {
+		registered[route] = true
+	}
+	expected := []util.Route{
+		{Method: "GET", Path: "/v1/fornecedores"},
+		{Method: "POST", Path: "/v1/fornecedores"},
+		{Method: "GET", Path: "/v1/fornecedores/{id}"},
+		{Method: "PUT", Path: "/v1/fornecedores/{id}"},
+		{Method: "DELETE", Path: "/v1/fornecedores/{id}"},
+		{Method: "GET", Path: "/v1/relatorios/financeiro"},
+		{Method: "", Path: "/v1/health"},
+	}
+	for _, route := range expected {
+		if !registered[route] {
+			t.Errorf("expected %s %s to be registered", route.Method, route.Path)
+		}
+	}
+	if registered[util.Route{Path: "/v1/relatorios/"}] {
+		t.Error("expected the relatorios mount point to be left out")
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	handler := (&Server{db: stubDB{}}).RegisterRoutes()
+
+	compact := httptest.NewRecorder()
+	handler.ServeHTTP(compact, httptest.NewRequest(http.MethodGet, "/v1/nao-existe", nil))
+	pretty := httptest.NewRecorder()
+	handler.ServeHTTP(pretty, httptest.NewRequest(http.MethodGet, "/v1/nao-existe?pretty=true", nil))
+
+	if strings.Contains(compact.Body.String(), "\n  ") {
+		t.Errorf("expected compact JSON by default; got %q", compact.Body.String())
+	}
+	if !strings.Contains(pretty.Body.String(), "\n  \"detail\": ") {
+		t.Errorf("expected indented JSON with ?pretty=true; got %q", pretty.Body.String())
+	}
+
+	var a, b map[string]any
+	if err := json.Unmarshal(compact.Body.Bytes(), &a); err != nil {
+		t.Fatalf("error decoding compact body. Err: %v", err)
+	}
+	if err := json.Unmarshal(pretty.Body.Bytes(), &b); err != nil {
+		t.Fatalf("error decoding pretty body. Err: %v", err)
+	}
+	if a["detail"] != b["detail"] {
+		t.Errorf("expected the same payload; got %v and %v", a, b)
+	}
+}
+
+func TestXMLContentNegotiation(t *testing.T) {
+	handler := (&Server{db: stubDB{}, adminToken: testAdminToken}).RegisterRoutes()
+
+	req := adminRequest(http.MethodGet, "/v1/admin/maintenance", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/xml" {
+		t.Errorf("expected Content-Type application/xml; got %q", got)
+	}
+	var doc struct {
+		XMLName xml.Name `xml:"response"`
+		Item    struct {
+			Enabled bool `xml:"enabled"`
+		} `xml:"item"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("expected well-formed XML; got %v in %s", err, rec.Body.String())
+	}
+
+	// Sem header Accept a resposta continua em JSON
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, adminRequest(http.MethodGet, "/v1/admin/maintenance", nil))
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected Content-Type application/json by default; got %q", got)
+	}
+
+	req = adminRequest(http.MethodGet, "/v1/admin/maintenance", nil)
+	req.Header.Set("Accept", "text/csv")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotAcceptable {
+		t.Errorf("expected status 406 for unsupported Accept; got %d", rec.Code)
+	}
+}
+
+func TestNegotiationAcceptsDeclaredMediaTypes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /stream", util.Produces(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}, "application/x-ndjson"))
+	mux.HandleFunc("GET /items", func(w http.ResponseWriter, r *http.Request) {
+		util.WriteJSON(w, http.StatusOK, []string{})
+	})
+	handler := (&Server{}).negotiateMiddleware(mux, mux)
+
+	tests := []struct {
+		path   string
+		accept string
+		status int
+	}{
+		{"/stream", "application/x-ndjson", http.StatusOK},
+		{"/stream", "application/x-ndjson;q=0", http.StatusNotAcceptable},
+		{"/stream", "text/csv", http.StatusNotAcceptable},
+		{"/items", "application/x-ndjson", http.StatusNotAcceptable},
+		{"/items", "application/json", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Accept", tt.accept)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("GET %s with Accept %q: expected status %d; got %d", tt.path, tt.accept, tt.status, rec.Code)
+		}
+	}
+}
+
+// O spec gerado pelo swag fica desatualizado quando uma rota é adicionada ou renomeada
+// sem regerar a documentação (make docs). Qualquer desvio fora dos conhecidos abaixo falha.
+func TestSwaggerSpecDiff(t *testing.T) {
+	s := &Server{db: stubDB{}}
+	s.RegisterRoutes()
+
+	diff, err := util.DiffRoutesVsSpec([]byte(docs.SwaggerInfo.ReadDoc()), s.routes.Routes(), "/v1")
+	if err != nil {
+		t.Fatalf("error reading swagger spec. Err: %v", err)
+	}
+
+	// As rotas POST <coleção>/validate são registradas em laço por validateHandler e
+	// documentadas uma única vez como /{collection}/validate
+	allowedUndocumented := make([]util.Route, 0, len(collectionPaths))
+	for _, path := range collectionPaths {
+		allowedUndocumented = append(allowedUndocumented, util.Route{Method: "POST", Path: path + "/validate"})
+	}
+	allowedUnimplemented := []util.Route{
+		{Method: "POST", Path: "/{collection}/validate"},
+		// trailingSlashHandler responde os caminhos fora de /v1, então não entra na tabela de rotas
+		{Method: "GET", Path: "/"},
+	}
+
+	for _, r := range diff.Undocumented {
+		if !slices.Contains(allowedUndocumented, r) {
+			t.Errorf("route %s %s is not documented in the swagger spec", r.Method, r.Path)
+		}
+	}
+	for _, r := range diff.Unimplemented {
+		if !slices.Contains(allowedUnimplemented, r) {
+			t.Errorf("swagger spec documents %s %s, which is not registered", r.Method, r.Path)
+		}
 	}
 }
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"sync"
+)
+
+// Decide quais requisições entram no log: respostas de erro (>= 400) sempre,
+// as bem-sucedidas apenas na fração `rate`
+type logSampler struct {
+	mu   sync.Mutex
+	rate float64
+	rng  *rand.Rand
+}
+
+func newLogSampler(rate float64, src rand.Source) *logSampler {
+	return &logSampler{
+		rate: rate,
+		rng:  rand.New(src),
+	}
+}
+
+func (ls *logSampler) sample(statusCode int) bool {
+	if statusCode >= http.StatusBadRequest || ls.rate >= 1 {
+		return true
+	}
+	if ls.rate <= 0 {
+		return false
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.rng.Float64() < ls.rate
+}
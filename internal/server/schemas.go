@@ -0,0 +1,93 @@
+package server
+
+import (
+	"edna/internal/model"
+	"edna/internal/util"
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// Corpos de criação expostos em /schemas/{entity}, pelo nome usado na rota
+var createSchemas = map[string]any{
+	"fornecedor":        model.FornecedorCreate{},
+	"cliente":           model.ClienteCreate{},
+	"funcionario":       model.FuncionarioCreate{},
+	"lote":              model.LoteCreate{},
+	"produto":           model.ProdutoCreate{},
+	"produto_comercial": model.ComercialCreate{},
+	"oferta":            model.OfertaCreate{},
+	"venda":             model.VendaCreate{},
+	"item_venda":        model.ItemVendaCreate{},
+	"item_oferta":       model.ItemOfertaCreate{},
+}
+
+// Rota de coleção de cada entidade de createSchemas, onde `POST <rota>/validate` é registrado
+var collectionPaths = map[string]string{
+	"fornecedor":        "/fornecedores",
+	"cliente":           "/clientes",
+	"funcionario":       "/funcionarios",
+	"lote":              "/lotes",
+	"produto":           "/produtos",
+	"produto_comercial": "/produtos/comercial",
+	"oferta":            "/ofertas",
+	"venda":             "/vendas",
+	"item_venda":        "/item_venda",
+	"item_oferta":       "/item_ofertas",
+}
+
+// Regras de negócio dos corpos de criação que vão além das tags `validate`
+type bodyValidator interface {
+	Validate() error
+}
+
+// @Summary Get the JSON Schema of a create request
+// @Description Returns the JSON Schema of the body accepted when creating the entity, generated from the same rules used by the server validation.
+// @Tags Server
+// @Produce json
+// @Param entity path string true "Entity name (fornecedor, cliente, funcionario, lote, produto, produto_comercial, oferta, venda, item_venda, item_oferta)"
+// @Success 200 {object} util.JSONSchema
+// @Failure 404 {object} types.ErrorResponse
+// @Router /schemas/{entity} [get]
+func (s *Server) schemaHandler(w http.ResponseWriter, r *http.Request) {
+	entity := r.PathValue("entity")
+	dto, ok := createSchemas[entity]
+	if !ok {
+		util.ErrorJSON(w, "Unknown entity `"+entity+"`.", http.StatusNotFound)
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, util.SchemaFor(entity, dto))
+}
+
+// @Summary Validate a create request without persisting it
+// @Description Runs the same validation as the create route of the entity and reports every problem found. Nothing is created.
+// @Tags Server
+// @Accept json
+// @Produce json
+// @Success 200 {object} util.ValidationResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Router /{collection}/validate [post]
+func (s *Server) validateHandler(entity string) http.HandlerFunc {
+	dtoType := reflect.TypeOf(createSchemas[entity])
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload := reflect.New(dtoType).Interface()
+		if err := util.ReadJSON(r, payload); err != nil {
+			util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var errs util.ValidationErrors
+		if err := util.Validate(payload); err != nil && !errors.As(err, &errs) {
+			util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// As regras de negócio só são verificadas nos corpos que passaram pelas tags, como no create
+		if v, ok := payload.(bodyValidator); ok && len(errs) == 0 {
+			if err := v.Validate(); err != nil {
+				errs = append(errs, util.FieldError{Message: err.Error()})
+			}
+		}
+
+		util.WriteJSON(w, http.StatusOK, util.ValidationResponse{Valid: len(errs) == 0, Errors: errs})
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"edna/internal/util"
+	"net/http"
+	"time"
+)
+
+// Consulta de leitura usada pelo self-test, atravessa handler, store e banco
+type selfTestStore interface {
+	Count(ctx context.Context, filter *util.Filter) (int64, error)
+}
+
+type selfTestResult struct {
+	Status    string  `json:"status"`
+	Check     string  `json:"check"`
+	Rows      int64   `json:"rows"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// @Summary Run a read-only self-test
+// @Description Counts the produtos through the regular store, validating the path from handler to database, and reports the latency. Unlike /health it runs a real query.
+// @Tags Server
+// @Produce json
+// @Param Authorization header string true "Bearer <ADMIN_TOKEN>"
+// @Success 200 {object} selfTestResult
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Failure 503 {object} selfTestResult
+// @Router /health/selftest [get]
+func (s *Server) selfTestHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	result := selfTestResult{Status: "up", Check: "produto.count"}
+	start := time.Now()
+	rows, err := s.selfTestStore.Count(ctx, &util.Filter{})
+	result.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusServiceUnavailable
+		result.Status = "down"
+		result.Error = err.Error()
+	} else {
+		result.Rows = rows
+	}
+	util.WriteJSON(w, status, result)
+}
@@ -2,13 +2,13 @@ package server
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"net/http"
-	"os"
-	"strconv"
+	"net/netip"
+	"sync/atomic"
 	"time"
 
-	_ "github.com/joho/godotenv/autoload"
-
+	"edna/internal/config"
 	"edna/internal/database"
 	"edna/internal/services/aplica_oferta"
 	"edna/internal/services/cliente"
@@ -21,11 +21,35 @@ import (
 	"edna/internal/services/produto"
 	"edna/internal/services/relatorio"
 	"edna/internal/services/venda"
+	"edna/internal/util"
 )
 
 type Server struct {
 	port int
 
+	// Modo de manutenção, pode ser alterado em tempo de execução
+	maintenance atomic.Bool
+	// Limite de requisições das rotas de relatório (nil desabilita)
+	relatorioLimiter *rateLimiter
+	// Proxies cujo header X-Real-IP identifica o cliente
+	trustedProxies []netip.Prefix
+	// Forma canônica das rotas: com barra final (true) ou sem (false)
+	preferTrailingSlash bool
+	// Espera por uma conexão livre do pool antes de responder 503 (0 não verifica)
+	poolWait time.Duration
+	// Amostragem do log de requisições (nil registra todas)
+	logSampler *logSampler
+	// Valor de `Access-Control-Max-Age` nas respostas de preflight (0 omite o header)
+	corsMaxAge int
+	// Valor do header Content-Security-Policy (vazio omite o header)
+	contentSecurityPolicy string
+	// Token exigido pelas rotas /admin (vazio recusa todas as requisições)
+	adminToken string
+	// Funcionalidades opcionais habilitadas neste deploy
+	features config.Features
+	// Circuit breaker das consultas ao banco, compartilhado pelos stores (nil desabilita)
+	breaker *util.CircuitBreaker
+
 	db                database.Service
 	fornecedorStore   *fornecedor.Store
 	produtoStore      *produto.Store
@@ -38,36 +62,86 @@ type Server struct {
 	itemOfertaStore   *item_oferta.Store
 	itemVendaStore    *item_venda.Store
 	aplicaOfertaStore *aplica_oferta.Store
+	// Rotas registradas em RegisterRoutes
+	routes *util.RouteTable
+	// Store consultado por /health/selftest
+	selfTestStore selfTestStore
 }
 
-func NewServer() *http.Server {
-	port, _ := strconv.Atoi(os.Getenv("PORT"))
+func NewServer(cfg config.Config) *http.Server {
+	var breaker *util.CircuitBreaker
+	if cfg.Database.BreakerThreshold > 0 {
+		breaker = util.NewCircuitBreaker(cfg.Database.BreakerThreshold, cfg.Database.BreakerCooldown)
+	}
+	loc := cfg.Timezone
+	if loc == nil {
+		loc = time.UTC
+	}
 
-	if port == 0 {
-		port = 8080
+	start := time.Now()
+	db := database.New(cfg.Database)
+	if health := db.Health(); health.Status == componentDown {
+		logComponent("database", componentDown, start, "name", cfg.Database.Name, "error", health.Error)
+	} else {
+		logComponent("database", health.Status, start, "name", cfg.Database.Name)
 	}
-	db := database.New()
+	storeDB := &util.DB{
+		DB:               db.Conn(),
+		MaxUnboundedRows: uint32(cfg.MaxUnboundedRows),
+		ReadRetries:      cfg.ReadRetries,
+		Breaker:          breaker,
+	}
+
+	start = time.Now()
 	NewServer := &Server{
-		port: port,
+		port:       cfg.Port,
+		features:   cfg.Features,
+		adminToken: cfg.AdminToken,
+		breaker:    breaker,
+
+		preferTrailingSlash: cfg.PreferTrailingSlash,
+		corsMaxAge:          cfg.CORSMaxAge,
+
+		contentSecurityPolicy: cfg.ContentSecurityPolicy,
 
 		db:                db,
-		fornecedorStore:   fornecedor.NewStore(db.Conn()),
-		produtoStore:      produto.NewStore(db.Conn()),
-		clienteStore:      cliente.NewStore(db.Conn()),
-		loteStore:         lote.NewStore(db.Conn()),
-		ofertaStore:       oferta.NewStore(db.Conn()),
-		vendaStore:        venda.NewStore(db.Conn()),
-		itemVendaStore:    item_venda.NewStore(db.Conn()),
-		itemOfertaStore:   item_oferta.NewStore(db.Conn()),
-		aplicaOfertaStore: aplica_oferta.NewStore(db.Conn()),
-		funcionarioStore:  funcionario.NewStore(db.Conn()),
-		relatorioStore:    relatorio.NewStore(db.Conn()),
+		fornecedorStore:   fornecedor.NewStore(storeDB),
+		produtoStore:      produto.NewStore(storeDB),
+		clienteStore:      cliente.NewStore(storeDB),
+		loteStore:         lote.NewStore(storeDB),
+		ofertaStore:       oferta.NewStore(storeDB),
+		vendaStore:        venda.NewStore(storeDB),
+		itemVendaStore:    item_venda.NewStore(storeDB, loc),
+		itemOfertaStore:   item_oferta.NewStore(storeDB),
+		aplicaOfertaStore: aplica_oferta.NewStore(storeDB),
+		funcionarioStore:  funcionario.NewStore(storeDB),
+		relatorioStore:    relatorio.NewStore(storeDB, loc),
 	}
+	NewServer.selfTestStore = NewServer.produtoStore
+	logComponent("repositories", componentOK, start)
+	NewServer.maintenance.Store(cfg.MaintenanceMode)
+
+	if cfg.Database.MaxOpenConns > 0 {
+		NewServer.poolWait = cfg.Database.PoolWait
+	}
+
+	if cfg.LogSampleRate < 1 {
+		NewServer.logSampler = newLogSampler(cfg.LogSampleRate, rand.NewPCG(uint64(time.Now().UnixNano()), 0))
+	}
+
+	NewServer.trustedProxies = cfg.TrustedProxies
+	if cfg.Features.Enabled(config.FeatureRelatorioRateLimit) && cfg.RelatorioRateLimit > 0 {
+		NewServer.relatorioLimiter = newRateLimiter(cfg.RelatorioRateLimit, time.Minute)
+	}
+
+	start = time.Now()
+	handler := NewServer.RegisterRoutes()
+	logComponent("handlers", componentOK, start, "routes", len(NewServer.routes.Routes()), "features", cfg.Features.All())
 
 	// Declare Server config
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", NewServer.port),
-		Handler:      NewServer.RegisterRoutes(),
+		Handler:      handler,
 		IdleTimeout:  time.Minute,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 30 * time.Second,
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"bytes"
+	"edna/internal/config"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewServerFromConfig(t *testing.T) {
+	// Nenhuma variável de ambiente é necessária: toda a configuração vem do Config.
+	cfg := config.Config{
+		Port:            9999,
+		MaintenanceMode: true,
+		AdminToken:      testAdminToken,
+		Database: config.DatabaseConfig{
+			Host:     "localhost",
+			Port:     "5432",
+			Name:     "edna",
+			Username: "edna",
+		},
+	}
+	srv := NewServer(cfg)
+
+	if srv.Addr != ":9999" {
+		t.Errorf("expected addr :9999; got %q", srv.Addr)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rr, adminRequest(http.MethodGet, "/v1/admin/maintenance", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rr.Code)
+	}
+	var status maintenanceStatus
+	if err := json.NewDecoder(rr.Body).Decode(&status); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+	if !status.Enabled {
+		t.Errorf("expected maintenance mode from config to be enabled")
+	}
+}
+
+func TestDisabledFeaturesAreNotInTheChain(t *testing.T) {
+	cfg := config.Config{
+		Port:               9999,
+		RelatorioRateLimit: 1,
+		AdminToken:         testAdminToken,
+		Features: config.Features{
+			config.FeatureRequestLog:         false,
+			config.FeatureRelatorioRateLimit: false,
+		},
+	}
+	srv := NewServer(cfg)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	for range 3 {
+		rr := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/relatorios/financeiro", nil))
+		if rr.Code == http.StatusTooManyRequests {
+			t.Fatal("expected relatorio rate limit to be disabled")
+		}
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected request log to be disabled; got %q", logs.String())
+	}
+
+	rr := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rr, adminRequest(http.MethodGet, "/v1/admin/features", nil))
+	var features map[string]bool
+	if err := json.NewDecoder(rr.Body).Decode(&features); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+	if features[config.FeatureRequestLog] || features[config.FeatureRelatorioRateLimit] {
+		t.Errorf("expected both features to be reported as disabled; got %v", features)
+	}
+}
+
+func TestStartupLogsComponentStatus(t *testing.T) {
+	var logs bytes.Buffer
+	startupLogger = slog.New(slog.NewJSONHandler(&logs, nil))
+	defer func() { startupLogger = nil }()
+
+	NewServer(config.Config{Port: 9999})
+
+	components := map[string]map[string]any{}
+	decoder := json.NewDecoder(&logs)
+	for decoder.More() {
+		var entry map[string]any
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatalf("error decoding log entry. Err: %v", err)
+		}
+		if component, ok := entry["component"].(string); ok {
+			components[component] = entry
+		}
+	}
+
+	db, ok := components["database"]
+	if !ok {
+		t.Fatalf("expected a status entry for the database; got %v", components)
+	}
+	if status := db["status"]; status != componentUp && status != componentDown {
+		t.Errorf("expected database status up or down; got %v", status)
+	}
+	if _, ok := db["duration"]; !ok {
+		t.Errorf("expected database entry to report its duration; got %v", db)
+	}
+	for _, component := range []string{"repositories", "handlers"} {
+		if _, ok := components[component]; !ok {
+			t.Errorf("expected a status entry for %s", component)
+		}
+	}
+}
+
+func TestLogSampling(t *testing.T) {
+	s := &Server{logSampler: newLogSampler(0.25, rand.NewPCG(1, 2))}
+	handler := s.logMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		w.WriteHeader(status)
+	}))
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	serve := func(status, n int) int {
+		logs.Reset()
+		for range n {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?status="+strconv.Itoa(status), nil))
+		}
+		return strings.Count(logs.String(), "\n")
+	}
+
+	if logged := serve(http.StatusNotFound, 100); logged != 100 {
+		t.Errorf("expected every 404 to be logged; got %d of 100", logged)
+	}
+	if logged := serve(http.StatusInternalServerError, 100); logged != 100 {
+		t.Errorf("expected every 500 to be logged; got %d of 100", logged)
+	}
+	if logged := serve(http.StatusOK, 1000); logged < 200 || logged > 300 {
+		t.Errorf("expected about 250 of 1000 successes to be logged; got %d", logged)
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Estados reportados na inicialização dos componentes
+const (
+	componentOK   = "ok"
+	componentUp   = "up"
+	componentDown = "down"
+)
+
+// Logger dos eventos de inicialização, nil usa slog.Default (substituído nos testes)
+var startupLogger *slog.Logger
+
+// Registra o estado de um componente ao fim da sua inicialização, com o tempo gasto desde start.
+// Componentes fora do ar são registrados como aviso: o servidor sobe mesmo assim e o
+// problema continua visível em /health.
+func logComponent(component, status string, start time.Time, attrs ...any) {
+	logger := startupLogger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	level := slog.LevelInfo
+	if status == componentDown {
+		level = slog.LevelWarn
+	}
+	args := append([]any{"component", component, "status", status, "duration", time.Since(start)}, attrs...)
+	logger.Log(context.Background(), level, "component initialized", args...)
+}
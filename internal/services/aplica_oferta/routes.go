@@ -5,7 +5,6 @@ import (
 	"edna/internal/model"
 	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -15,6 +14,7 @@ type Handler struct {
 
 type AplicaOfertaStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.AplicaOferta, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	GetByID(ctx context.Context, id int64) (*model.AplicaOferta, error)
 	Create(ctx context.Context, c *model.AplicaOferta) error
 	Update(ctx context.Context, c *model.AplicaOferta) error
@@ -25,14 +25,27 @@ func NewHandler(store AplicaOfertaStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /aplica_oferta", h.getAll)
+	mux.HandleFunc("GET /aplica_oferta/count", h.count)
 	mux.HandleFunc("POST /aplica_oferta", h.create)
 	mux.HandleFunc("GET /aplica_oferta/{id}", h.fetch)
 	mux.HandleFunc("PUT /aplica_oferta/{id}", h.update)
 	mux.HandleFunc("DELETE /aplica_oferta/{id}", h.delete)
 }
 
+// @Summary List AplicaOferta
+// @Tags AplicaOferta
+// @Produce json
+// @Param filter-id_oferta query int false "Filter by id_oferta using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)"
+// @Param filter-id_venda query int false "Filter by id_venda using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)"
+// @Param filter-id_item_venda query int false "Filter by id_item_venda using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)"
+// @Param sort query string false "Sort fields: id_oferta, id_venda, id_item_venda. Prefix with '-' for desc. Comma separated for multiple fields"
+// @Param offset query int false "Pagination offset (default 0)"
+// @Param limit query int false "Pagination limit (default 10)"
+// @Success 200 {array} model.AplicaOferta
+// @Failure 500 {object} types.ErrorResponse
+// @Router /aplica_oferta [get]
 func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -45,9 +58,16 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 
 	aplicaOfertas, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	util.SetPaginationHeaders(w, r, filters, len(aplicaOfertas), total)
 
 	err = util.WriteJSON(w, http.StatusOK, aplicaOfertas)
 	if err != nil {
@@ -55,6 +75,42 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// @Summary Count AplicaOferta
+// @Description Returns how many rows match the filters accepted by GET /aplica_oferta, ignoring sort and pagination.
+// @Tags AplicaOferta
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /aplica_oferta/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewAplicaOfertaFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
+// @Summary Create AplicaOferta
+// @Tags AplicaOferta
+// @Accept json
+// @Produce json
+// @Param payload body model.AplicaOfertaResponse true "AplicaOferta payload"
+// @Success 201 {object} model.AplicaOferta
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse
+// @Router /aplica_oferta [post]
 func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -65,7 +121,7 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.AplicaOfertaResponse
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
@@ -74,13 +130,22 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	model := payload.ToAplicaOferta()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
 	util.WriteJSON(w, http.StatusCreated, model)
 }
 
+// @Summary Get AplicaOferta by ID
+// @Tags AplicaOferta
+// @Produce json
+// @Param id path int true "AplicaOferta ID"
+// @Success 200 {object} model.AplicaOferta
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /aplica_oferta/{id} [get]
 func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -97,7 +162,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Oferta not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -107,6 +172,17 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// @Summary Update AplicaOferta
+// @Tags AplicaOferta
+// @Accept json
+// @Produce json
+// @Param id path int true "AplicaOferta ID"
+// @Param payload body model.AplicaOfertaResponse true "AplicaOferta payload"
+// @Success 200 {object} model.AplicaOferta
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse
+// @Router /aplica_oferta/{id} [put]
 func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -118,7 +194,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.AplicaOfertaResponse
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
@@ -132,13 +208,22 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Oferta not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
 	util.WriteJSON(w, http.StatusOK, model)
 }
 
+// @Summary Delete AplicaOferta
+// @Tags AplicaOferta
+// @Produce json
+// @Param id path int true "AplicaOferta ID"
+// @Success 200 {object} model.AplicaOferta
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse
+// @Router /aplica_oferta/{id} [delete]
 func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -155,7 +240,7 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Oferta not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -9,10 +9,10 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{db}
 }
 
@@ -26,6 +26,10 @@ type AplicaOfertaDetail struct {
 
 // Busca todas as ofertas aplicadas a uma venda específica.
 func (s *Store) GetByVendaID(ctx context.Context, idVenda int64) ([]AplicaOfertaDetail, error) {
+	return util.RetryRead(ctx, s.db, func() ([]AplicaOfertaDetail, error) { return s.getByVendaID(ctx, idVenda) })
+}
+
+func (s *Store) getByVendaID(ctx context.Context, idVenda int64) ([]AplicaOfertaDetail, error) {
 	query := `
 		SELECT
 			ao.id_aplica_oferta, ao.id_oferta, ao.id_venda, ao.id_item_venda,
@@ -45,6 +49,9 @@ func (s *Store) GetByVendaID(ctx context.Context, idVenda int64) ([]AplicaOferta
 
 	var ofertas []AplicaOfertaDetail
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var o AplicaOfertaDetail
 		err := rows.Scan(
 			&o.IDAplicaOferta, &o.IDOferta, &o.IDVenda, &o.IDItemVenda,
@@ -55,18 +62,29 @@ func (s *Store) GetByVendaID(ctx context.Context, idVenda int64) ([]AplicaOferta
 		}
 		ofertas = append(ofertas, o)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return ofertas, nil
 }
 
 // Deleta entradas com base no id_item_venda.
 // Será usado pelo serviço de item_venda dentro de uma transação ao deletar um item.
 func (s *Store) DeleteByItemVendaID(ctx context.Context, idItemVenda int64) error {
+	return s.db.RecordWrite(s.deleteByItemVendaID(ctx, idItemVenda))
+}
+
+func (s *Store) deleteByItemVendaID(ctx context.Context, idItemVenda int64) error {
 	query := `DELETE FROM aplica_oferta WHERE id_item_venda = $1;`
 	_, err := s.db.ExecContext(ctx, query, idItemVenda)
 	return err
 }
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.AplicaOferta, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.AplicaOferta, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.AplicaOferta, error) {
 
 	query := `
 		SELECT id_aplica_oferta, id_oferta, id_venda, id_item_venda
@@ -77,10 +95,14 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.AplicaO
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	aplicaOfertas := make([]model.AplicaOferta, 0)
 
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var c model.AplicaOferta
 
 		err := rows.Scan(&c.IDAplicaOferta, &c.IDOferta, &c.IDVenda, &c.IDItemVenda)
@@ -91,11 +113,24 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.AplicaO
 
 		aplicaOfertas = append(aplicaOfertas, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return aplicaOfertas, nil
 }
 
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM aplica_oferta AS c"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "c")
+}
+
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.AplicaOferta, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.AplicaOferta, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.AplicaOferta, error) {
 	query := `
 		SELECT id_aplica_oferta, id_oferta, id_venda, id_item_venda
 		FROM aplica_oferta
@@ -119,6 +154,10 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.AplicaOferta, err
 }
 
 func (s *Store) Create(ctx context.Context, c *model.AplicaOferta) error {
+	return s.db.RecordWrite(s.create(ctx, c))
+}
+
+func (s *Store) create(ctx context.Context, c *model.AplicaOferta) error {
 	query := `
 		INSERT INTO aplica_oferta (id_oferta, id_venda, id_item_venda)
 		VALUES ($1, $2, $3)
@@ -130,6 +169,10 @@ func (s *Store) Create(ctx context.Context, c *model.AplicaOferta) error {
 }
 
 func (s *Store) Update(ctx context.Context, c *model.AplicaOferta) error {
+	return s.db.RecordWrite(s.update(ctx, c))
+}
+
+func (s *Store) update(ctx context.Context, c *model.AplicaOferta) error {
 	query := `
 		UPDATE aplica_oferta
 		SET id_oferta = $2, id_venda = $3, id_item_venda = $4
@@ -152,6 +195,11 @@ func (s *Store) Update(ctx context.Context, c *model.AplicaOferta) error {
 }
 
 func (s *Store) Delete(ctx context.Context, id int64) (*model.AplicaOferta, error) {
+	result, err := s.delete(ctx, id)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id int64) (*model.AplicaOferta, error) {
 	query := `
 		DELETE FROM aplica_oferta
 		WHERE id_aplica_oferta = $1
@@ -5,7 +5,6 @@ import (
 	"edna/internal/model"
 	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -15,20 +14,24 @@ type Handler struct {
 
 type ClienteStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.Cliente, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]model.ClienteWithSaldo, error)
+	CountWithSaldo(ctx context.Context, filter util.Filter) (int64, error)
 	Create(ctx context.Context, props *model.Cliente) error
 	GetByID(ctx context.Context, id int64) (*model.Cliente, error)
 	GetByIDWithSaldo(ctx context.Context, id int64) (*model.ClienteWithSaldo, error)
 	Update(ctx context.Context, props *model.Cliente) error
 	Delete(ctx context.Context, id int64) (*model.Cliente, error)
+	DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error)
 }
 
 func NewHandler(store ClienteStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /clientes", h.getAll)
+	mux.HandleFunc("GET /clientes/count", h.count)
 	mux.HandleFunc("GET /clientes/saldo", h.getAllWithSaldo)
 	mux.HandleFunc("POST /clientes", h.create)
 	mux.HandleFunc("GET /clientes/{id}", h.fetch)
@@ -59,15 +62,49 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 	clientes, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	util.SetPaginationHeaders(w, r, filters, len(clientes), total)
+
 	err = util.WriteJSON(w, http.StatusOK, clientes)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// @Summary Count Clientes
+// @Description Returns how many rows match the filters accepted by GET /clientes, ignoring sort and pagination.
+// @Tags Cliente
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /clientes/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewClienteFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
 // @Summary List Clients
 // @Tags Cliente
 // @Produce json
@@ -79,7 +116,7 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 // @Param limit query int false "Pagination limit (default 10)"
 // @Success 200 {array} model.ClienteWithSaldo
 // @Failure 500 {object} types.ErrorResponse
-// @Router /clientes/saldos [get]
+// @Router /clientes/saldo [get]
 func (h *Handler) getAllWithSaldo(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -91,9 +128,16 @@ func (h *Handler) getAllWithSaldo(w http.ResponseWriter, r *http.Request) {
 	}
 	clientes, err := h.store.GetAllWithSaldo(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	total, err := h.store.CountWithSaldo(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	util.SetPaginationHeaders(w, r, filters, len(clientes), total)
+
 	err = util.WriteJSON(w, http.StatusOK, clientes)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
@@ -119,16 +163,20 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.ClienteCreate
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToCliente()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -160,7 +208,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Cliente not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -191,11 +239,15 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.ClienteCreate
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToCliente()
 	model.Id = id
@@ -205,7 +257,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Cliente not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -237,7 +289,7 @@ func (h *Handler) fetchSaldo(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Cliente not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -248,6 +300,7 @@ func (h *Handler) fetchSaldo(w http.ResponseWriter, r *http.Request) {
 // @Tags Cliente
 // @Produce json
 // @Param id path int true "Cliente ID"
+// @Param dry_run query bool false "Only report what would be deleted in cascade, returning a model.DeletePreview"
 // @Success 200 {object} model.Cliente
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 422 {object} types.ErrorResponse
@@ -262,13 +315,27 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if util.IsDryRun(r) {
+		preview, err := h.store.DeletePreview(ctx, id)
+		if err != nil {
+			if err == types.ErrNotFound {
+				util.ErrorJSON(w, "Cliente not found.", http.StatusNotFound)
+				return
+			}
+			util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		util.WriteJSON(w, http.StatusOK, preview)
+		return
+	}
+
 	model, err := h.store.Delete(ctx, id)
 	if err != nil {
 		if err == types.ErrNotFound {
 			util.ErrorJSON(w, "Cliente not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
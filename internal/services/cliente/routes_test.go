@@ -0,0 +1,222 @@
+package cliente
+
+import (
+	"context"
+	"edna/internal/model"
+	"edna/internal/types"
+	"edna/internal/util"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Implementa apenas os métodos usados nos testes, os demais entram em pânico
+type stubStore struct {
+	ClienteStore
+	clientes map[int64]model.Cliente
+	cascata  map[int64]map[string]int64
+}
+
+func (s *stubStore) Delete(ctx context.Context, id int64) (*model.Cliente, error) {
+	c, ok := s.clientes[id]
+	if !ok {
+		return nil, types.ErrNotFound
+	}
+	delete(s.clientes, id)
+	return &c, nil
+}
+
+func (s *stubStore) GetByID(ctx context.Context, id int64) (*model.Cliente, error) {
+	c, ok := s.clientes[id]
+	if !ok {
+		return nil, types.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (s *stubStore) DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	if _, ok := s.clientes[id]; !ok {
+		return nil, types.ErrNotFound
+	}
+	return &model.DeletePreview{Entidade: "cliente", Id: id, Cascata: s.cascata[id]}, nil
+}
+
+// Aplica apenas o filtro `filter-nome` com o operador eq
+func (s *stubStore) matching(filter util.Filter) []model.Cliente {
+	clientes := make([]model.Cliente, 0)
+	for _, c := range s.clientes {
+		if f, ok := filter.Filters["nome"]; ok && f.Value != c.Nome {
+			continue
+		}
+		clientes = append(clientes, c)
+	}
+	return clientes
+}
+
+func (s *stubStore) GetAll(ctx context.Context, filter util.Filter) ([]model.Cliente, error) {
+	return s.matching(filter), nil
+}
+
+func (s *stubStore) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	return int64(len(s.matching(filter))), nil
+}
+
+func (s *stubStore) GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]model.ClienteWithSaldo, error) {
+	clientes := make([]model.ClienteWithSaldo, 0)
+	for _, c := range s.matching(filter) {
+		clientes = append(clientes, model.ClienteWithSaldo{Cliente: c})
+	}
+	return clientes, nil
+}
+
+func (s *stubStore) CountWithSaldo(ctx context.Context, filter util.Filter) (int64, error) {
+	return int64(len(s.matching(filter))), nil
+}
+
+func newStubMux(store *stubStore) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewHandler(store).RegisterRoutes(mux)
+	return mux
+}
+
+func TestDeleteDryRun(t *testing.T) {
+	store := &stubStore{
+		clientes: map[int64]model.Cliente{7: {Id: 7, Nome: "Maria"}},
+		cascata:  map[int64]map[string]int64{7: {"venda": 2, "item_venda": 5, "aplica_oferta": 1}},
+	}
+	mux := newStubMux(store)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/clientes/7?dry_run=true", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rec.Code)
+	}
+	var preview model.DeletePreview
+	if err := json.NewDecoder(rec.Body).Decode(&preview); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+	if preview.Entidade != "cliente" || preview.Id != 7 {
+		t.Errorf("unexpected preview target: %+v", preview)
+	}
+	expected := map[string]int64{"venda": 2, "item_venda": 5, "aplica_oferta": 1}
+	for table, count := range expected {
+		if preview.Cascata[table] != count {
+			t.Errorf("expected %d rows of %s; got %d", count, table, preview.Cascata[table])
+		}
+	}
+	if _, ok := store.clientes[7]; !ok {
+		t.Errorf("dry run must not delete the cliente")
+	}
+
+	// Sem dry_run o registro é removido
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/clientes/7", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rec.Code)
+	}
+	if _, ok := store.clientes[7]; ok {
+		t.Errorf("expected cliente to be deleted")
+	}
+}
+
+func TestDeleteDryRunNotFound(t *testing.T) {
+	mux := newStubMux(&stubStore{clientes: map[int64]model.Cliente{}})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/clientes/99?dry_run=true", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404; got %d", rec.Code)
+	}
+}
+
+func TestCountMatchesList(t *testing.T) {
+	mux := newStubMux(&stubStore{clientes: map[int64]model.Cliente{
+		1: {Id: 1, Nome: "Maria"},
+		2: {Id: 2, Nome: "João"},
+		3: {Id: 3, Nome: "Maria"},
+	}})
+
+	for _, query := range []string{"", "?filter-nome=eq.Maria", "?filter-nome=eq.Ana"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clientes"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 listing %q; got %d", query, rec.Code)
+		}
+		var clientes []model.Cliente
+		if err := json.NewDecoder(rec.Body).Decode(&clientes); err != nil {
+			t.Fatalf("error decoding list body. Err: %v", err)
+		}
+
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clientes/count"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200 counting %q; got %d", query, rec.Code)
+		}
+		var count types.CountResponse
+		if err := json.NewDecoder(rec.Body).Decode(&count); err != nil {
+			t.Fatalf("error decoding count body. Err: %v", err)
+		}
+		if count.Count != int64(len(clientes)) {
+			t.Errorf("expected count %d for %q; got %d", len(clientes), query, count.Count)
+		}
+	}
+}
+
+func TestCountInvalidFilter(t *testing.T) {
+	mux := newStubMux(&stubStore{clientes: map[int64]model.Cliente{}})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clientes/count?filter-nome=gt.Maria", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400; got %d", rec.Code)
+	}
+}
+
+func TestFetchXMLUsesJSONNames(t *testing.T) {
+	nascimento := model.NewDate(time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC))
+	mux := newStubMux(&stubStore{clientes: map[int64]model.Cliente{
+		7: {Id: 7, Nome: "Maria", DataNascimento: &nascimento},
+	}})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(util.WithXML(rec), httptest.NewRequest(http.MethodGet, "/clientes/7", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rec.Code)
+	}
+
+	var doc struct {
+		Item struct {
+			Id             int64  `xml:"id"`
+			Nome           string `xml:"nome"`
+			DataNascimento string `xml:"data_nascimento"`
+		} `xml:"item"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("expected well-formed XML; got %v in %s", err, rec.Body.String())
+	}
+	if doc.Item.Id != 7 || doc.Item.Nome != "Maria" || doc.Item.DataNascimento != "1990-05-17" {
+		t.Errorf("expected snake_case elements matching the JSON names; got %s", rec.Body.String())
+	}
+}
+
+func TestGetAllWithSaldoSetsTotalCount(t *testing.T) {
+	store := &stubStore{clientes: map[int64]model.Cliente{
+		1: {Id: 1, Nome: "Maria"},
+		2: {Id: 2, Nome: "João"},
+		3: {Id: 3, Nome: "Maria"},
+	}}
+	mux := newStubMux(store)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clientes/saldo?filter-nome=eq.Maria", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rec.Code)
+	}
+	if got := rec.Header().Get("X-Total-Count"); got != "2" {
+		t.Errorf("expected X-Total-Count 2; got %q", got)
+	}
+}
@@ -12,23 +12,31 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{db}
 }
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Cliente, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Cliente, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.Cliente, error) {
 	query := "SELECT id_cliente, nome, cpf, data_nascimento FROM Cliente AS c"
 
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, &filter, "c")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	clientes := make([]model.Cliente, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var c model.Cliente
 		err = rows.Scan(&c.Id, &c.Nome, &c.CPF, &c.DataNascimento)
 		if err != nil {
@@ -36,26 +44,33 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Cliente
 		}
 		clientes = append(clientes, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return clientes, nil
 }
 
-func (s *Store) GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]model.ClienteWithSaldo, error) {
-	// Criamos uma lista de ids de clientes que estão devendo dinheiro
-	// Juntamos com clientes e substituimos por zero valores nulos.
-	query := `
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM Cliente AS c"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "c")
+}
+
+// Criamos uma lista de ids de clientes que estão devendo dinheiro, para juntar com clientes
+// substituindo por zero os saldos nulos.
+const clienteDevedorQuery = `
 	WITH ClienteDevedor AS (
 		SELECT id_cliente, COALESCE(SUM(quantidade * valor_unitario), 0)::numeric(12, 2) as saldo_devedor
 		FROM Venda
 		LEFT JOIN item_venda USING(id_venda)
 	 	WHERE data_hora_pagamento IS NULL
 		GROUP BY id_cliente
-	) SELECT id_cliente, nome, cpf, data_nascimento,
-		COALESCE(saldo_devedor, 0)::numeric(12, 2)
-		FROM Cliente
-		LEFT JOIN ClienteDevedor USING(id_cliente)
-	`
-	// Constroi a query de filtros manualmente
-	var values []any
+	)`
+
+// Constroi as condições (WHERE) de filter manualmente, pois saldo_devedor não é uma coluna
+// de Cliente, adicionando os valores da query em values
+func saldoWhereQuery(filter util.Filter, values *[]any) string {
+	var query string
 	i := 0
 	for k, v := range filter.Filters {
 		// reescrever saldo_devedor como zero caso seja nulo
@@ -69,33 +84,49 @@ func (s *Store) GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]mode
 		}
 		switch v.Operator {
 		case "lt":
-			values = append(values, v.Value)
-			query += fmt.Sprintf(" %s < $%d", k, len(values))
+			*values = append(*values, v.Value)
+			query += fmt.Sprintf(" %s < $%d", k, len(*values))
 		case "gt":
-			values = append(values, v.Value)
-			query += fmt.Sprintf(" %s > $%d", k, len(values))
+			*values = append(*values, v.Value)
+			query += fmt.Sprintf(" %s > $%d", k, len(*values))
 		case "eq":
-			values = append(values, v.Value)
-			query += fmt.Sprintf(" %s = $%d", k, len(values))
+			*values = append(*values, v.Value)
+			query += fmt.Sprintf(" %s = $%d", k, len(*values))
 		case "le":
-			values = append(values, v.Value)
-			query += fmt.Sprintf(" %s <= $%d", k, len(values))
+			*values = append(*values, v.Value)
+			query += fmt.Sprintf(" %s <= $%d", k, len(*values))
 		case "ge":
-			values = append(values, v.Value)
-			query += fmt.Sprintf(" %s >= $%d", k, len(values))
+			*values = append(*values, v.Value)
+			query += fmt.Sprintf(" %s >= $%d", k, len(*values))
 		case "ne":
-			values = append(values, v.Value)
-			query += fmt.Sprintf(" %s != $%d", k, len(values))
+			*values = append(*values, v.Value)
+			query += fmt.Sprintf(" %s != $%d", k, len(*values))
 		case "like":
-			values = append(values, v.Value)
-			query += fmt.Sprintf(" %s LIKE '%%' || $%d || '%%'", k, len(values))
+			*values = append(*values, v.Value)
+			query += fmt.Sprintf(" %s LIKE '%%' || $%d || '%%'", k, len(*values))
 		case "ilike":
-			values = append(values, v.Value)
-			query += fmt.Sprintf(" %s ILIKE '%%' || $%d || '%%'", k, len(values))
+			*values = append(*values, v.Value)
+			query += fmt.Sprintf(" %s ILIKE '%%' || $%d || '%%'", k, len(*values))
 		default:
 		}
 		i += 1
 	}
+	return query
+}
+
+func (s *Store) GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]model.ClienteWithSaldo, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.ClienteWithSaldo, error) { return s.getAllWithSaldo(ctx, filter) })
+}
+
+func (s *Store) getAllWithSaldo(ctx context.Context, filter util.Filter) ([]model.ClienteWithSaldo, error) {
+	query := clienteDevedorQuery + `
+	SELECT id_cliente, nome, cpf, data_nascimento,
+		COALESCE(saldo_devedor, 0)::numeric(12, 2)
+		FROM Cliente
+		LEFT JOIN ClienteDevedor USING(id_cliente)
+	`
+	var values []any
+	query += saldoWhereQuery(filter, &values)
 
 	// ordenação
 	for i, v := range filter.Sorts {
@@ -113,6 +144,7 @@ func (s *Store) GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]mode
 	}
 
 	// paginação
+	filter = filter.Bounded(s.db.MaxUnboundedRows)
 	if filter.Offset > 0 {
 		values = append(values, filter.Offset)
 		query += " OFFSET $" + strconv.Itoa(len(values))
@@ -122,7 +154,7 @@ func (s *Store) GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]mode
 		query += " LIMIT $" + strconv.Itoa(len(values))
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, values)
+	rows, err := s.db.QueryContext(ctx, query, values...)
 	if err != nil {
 		return nil, err
 	}
@@ -130,6 +162,9 @@ func (s *Store) GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]mode
 
 	clientes := make([]model.ClienteWithSaldo, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var c model.ClienteWithSaldo
 		err = rows.Scan(&c.Id, &c.Nome, &c.CPF, &c.DataNascimento, &c.SaldoDevedor)
 		if err != nil {
@@ -137,10 +172,33 @@ func (s *Store) GetAllWithSaldo(ctx context.Context, filter util.Filter) ([]mode
 		}
 		clientes = append(clientes, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return clientes, nil
 }
 
+// Conta quantos clientes satisfazem o filtro de GetAllWithSaldo, ignorando a paginação
+func (s *Store) CountWithSaldo(ctx context.Context, filter util.Filter) (int64, error) {
+	query := clienteDevedorQuery + `
+	SELECT COUNT(*)
+		FROM Cliente
+		LEFT JOIN ClienteDevedor USING(id_cliente)
+	`
+	var values []any
+	query += saldoWhereQuery(filter, &values)
+	return util.RetryRead(ctx, s.db, func() (int64, error) {
+		var count int64
+		err := s.db.QueryRowContext(ctx, query, values...).Scan(&count)
+		return count, err
+	})
+}
+
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.Cliente, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.Cliente, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.Cliente, error) {
 	query := "SELECT id_cliente, nome, cpf, data_nascimento FROM Cliente WHERE id_cliente = $1;"
 	row := s.db.QueryRowContext(ctx, query, id)
 
@@ -156,6 +214,10 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.Cliente, error) {
 }
 
 func (s *Store) GetByIDWithSaldo(ctx context.Context, id int64) (*model.ClienteWithSaldo, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.ClienteWithSaldo, error) { return s.getByIDWithSaldo(ctx, id) })
+}
+
+func (s *Store) getByIDWithSaldo(ctx context.Context, id int64) (*model.ClienteWithSaldo, error) {
 	query := `
 	WITH ClienteDevedor AS (
 		SELECT id_cliente, COALESCE(SUM(quantidade * valor_unitario), 0)::numeric(12, 2) as saldo_devedor
@@ -183,12 +245,20 @@ func (s *Store) GetByIDWithSaldo(ctx context.Context, id int64) (*model.ClienteW
 }
 
 func (s *Store) Create(ctx context.Context, props *model.Cliente) error {
+	return s.db.RecordWrite(s.create(ctx, props))
+}
+
+func (s *Store) create(ctx context.Context, props *model.Cliente) error {
 	query := "INSERT INTO Cliente (nome, cpf, data_nascimento) VALUES ($1, $2, $3) RETURNING id_cliente;"
 	res := s.db.QueryRowContext(ctx, query, props.Nome, props.CPF, props.DataNascimento)
 	return res.Scan(&props.Id)
 }
 
 func (s *Store) Update(ctx context.Context, props *model.Cliente) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.Cliente) error {
 	query := "UPDATE Cliente SET nome = $1, cpf = $2, data_nascimento = $3 WHERE id_cliente = $4;"
 	res, err := s.db.ExecContext(ctx, query, props.Nome, props.CPF, props.DataNascimento, props.Id)
 	if err != nil {
@@ -205,6 +275,11 @@ func (s *Store) Update(ctx context.Context, props *model.Cliente) error {
 }
 
 func (s *Store) Delete(ctx context.Context, id int64) (*model.Cliente, error) {
+	result, err := s.delete(ctx, id)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id int64) (*model.Cliente, error) {
 	query := "DELETE FROM Cliente WHERE id_cliente = $1 RETURNING id_cliente, nome, cpf, data_nascimento;"
 	var m model.Cliente
 	row := s.db.QueryRowContext(ctx, query, id)
@@ -217,3 +292,26 @@ func (s *Store) Delete(ctx context.Context, id int64) (*model.Cliente, error) {
 	}
 	return &m, nil
 }
+
+// Linhas removidas em cascata junto com o cliente, na ordem das tabelas passadas a CountCascade
+const deletePreviewQuery = `SELECT
+		(SELECT COUNT(*) FROM Venda WHERE id_cliente = c.id_cliente),
+		(SELECT COUNT(*) FROM item_venda iv JOIN Venda v ON v.id_venda = iv.id_venda WHERE v.id_cliente = c.id_cliente),
+		(SELECT COUNT(*) FROM aplica_oferta ao JOIN Venda v ON v.id_venda = ao.id_venda WHERE v.id_cliente = c.id_cliente)
+	FROM Cliente c WHERE c.id_cliente = $1;`
+
+// Conta o que seria removido em cascata pelo Delete, sem remover nada.
+func (s *Store) DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.DeletePreview, error) { return s.deletePreview(ctx, id) })
+}
+
+func (s *Store) deletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	cascata, err := util.CountCascade(s.db, ctx, deletePreviewQuery, id, "venda", "item_venda", "aplica_oferta")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, types.ErrNotFound
+		}
+		return nil, err
+	}
+	return &model.DeletePreview{Entidade: "cliente", Id: id, Cascata: cascata}, nil
+}
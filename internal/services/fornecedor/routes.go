@@ -3,8 +3,8 @@ package fornecedor
 import (
 	"context"
 	"edna/internal/model"
+	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -16,10 +16,12 @@ type Handler struct {
 
 type FornecedorStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.Fornecedor, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	Create(ctx context.Context, props *model.Fornecedor) error
 	GetByID(ctx context.Context, id int64) (*model.Fornecedor, error)
 	Update(ctx context.Context, props *model.Fornecedor) error
 	Delete(ctx context.Context, id int64) (*model.Fornecedor, error)
+	DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error)
 }
 
 
@@ -27,8 +29,9 @@ func NewHandler(store FornecedorStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /fornecedores", h.getAll)
+	mux.HandleFunc("GET /fornecedores/count", h.count)
 	mux.HandleFunc("POST /fornecedores", h.create)
 	mux.HandleFunc("GET /fornecedores/{id}", h.fetch)
 	mux.HandleFunc("PUT /fornecedores/{id}", h.update)
@@ -57,15 +60,49 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 	fornecedores, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	util.SetPaginationHeaders(w, r, filters, len(fornecedores), total)
+
 	err = util.WriteJSON(w, http.StatusOK, fornecedores)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// @Summary Count Fornecedores
+// @Description Returns how many rows match the filters accepted by GET /fornecedores, ignoring sort and pagination.
+// @Tags Fornecedor
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /fornecedores/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewFornecedorFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
 // @Summary Create Fornecedor
 // @Tags Fornecedor
 // @Accept json
@@ -85,16 +122,20 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.FornecedorCreate
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToFornecedor()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -122,7 +163,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 
 	fornecedor, err := h.store.GetByID(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 	if fornecedor == nil {
@@ -157,17 +198,21 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.FornecedorCreate
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToFornecedor()
 	model.Id = id
 	err = h.store.Update(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -178,6 +223,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 // @Tags Fornecedor
 // @Produce json
 // @Param id path int true "Fornecedor ID"
+// @Param dry_run query bool false "Only report what would be deleted in cascade, returning a model.DeletePreview. Sold lotes block the delete and are listed in bloqueios"
 // @Success 200 {object} model.Fornecedor
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 422 {object} types.ErrorResponse
@@ -192,9 +238,23 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if util.IsDryRun(r) {
+		preview, err := h.store.DeletePreview(ctx, id)
+		if err != nil {
+			if err == types.ErrNotFound {
+				util.ErrorJSON(w, "Fornecedor not found.", http.StatusNotFound)
+				return
+			}
+			util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		util.WriteJSON(w, http.StatusOK, preview)
+		return
+	}
+
 	model, err := h.store.Delete(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -9,24 +9,32 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{db}
 }
 
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Fornecedor, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Fornecedor, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.Fornecedor, error) {
 	query := "SELECT id_fornecedor, nome, CNPJ FROM Fornecedor AS f"
 
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, &filter, "f")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	fornecedores := make([]model.Fornecedor, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var fornecedor model.Fornecedor
 		err = rows.Scan(&fornecedor.Id, &fornecedor.Nome, &fornecedor.CNPJ)
 		if err != nil {
@@ -34,12 +42,25 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Fornece
 		}
 		fornecedores = append(fornecedores, fornecedor)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return fornecedores, nil
 }
 
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM Fornecedor AS f"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "f")
+}
+
 
 func (s *Store) Create(ctx context.Context, props *model.Fornecedor) error {
+	return s.db.RecordWrite(s.create(ctx, props))
+}
+
+func (s *Store) create(ctx context.Context, props *model.Fornecedor) error {
 	query := "INSERT INTO Fornecedor (nome, CNPJ) VALUES ($1, $2) RETURNING id_fornecedor;"
 
 	res := s.db.QueryRowContext(ctx, query, props.Nome, props.CNPJ)
@@ -47,6 +68,10 @@ func (s *Store) Create(ctx context.Context, props *model.Fornecedor) error {
 }
 
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.Fornecedor, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.Fornecedor, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.Fornecedor, error) {
 	query := "SELECT id_fornecedor, nome, CNPJ FROM Fornecedor WHERE id_fornecedor = $1;"
 
 	row := s.db.QueryRowContext(ctx, query, id)
@@ -61,6 +86,10 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.Fornecedor, error
 }
 
 func (s *Store) Update(ctx context.Context, props *model.Fornecedor) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.Fornecedor) error {
 	query := "UPDATE Fornecedor SET nome = $1, CNPJ = $2 WHERE id_fornecedor = $3;"
 
 	res, err := s.db.ExecContext(ctx, query, props.Nome, props.CNPJ, props.Id)
@@ -78,6 +107,11 @@ func (s *Store) Update(ctx context.Context, props *model.Fornecedor) error {
 }
 
 func (s *Store) Delete(ctx context.Context, id int64) (*model.Fornecedor, error) {
+	result, err := s.delete(ctx, id)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id int64) (*model.Fornecedor, error) {
 	query := "DELETE FROM Fornecedor WHERE id_fornecedor = $1 RETURNING id_fornecedor, nome, CNPJ;"
 
 	var model model.Fornecedor
@@ -88,3 +122,31 @@ func (s *Store) Delete(ctx context.Context, id int64) (*model.Fornecedor, error)
 	}
 	return &model, nil
 }
+
+// Lotes removidos em cascata junto com o fornecedor e itens de venda desses lotes, que
+// impedem a remoção (item_venda.id_lote é ON DELETE RESTRICT)
+const deletePreviewQuery = `SELECT
+		(SELECT COUNT(*) FROM Lote WHERE id_fornecedor = f.id_fornecedor),
+		(SELECT COUNT(*) FROM item_venda iv JOIN Lote l ON l.id_lote = iv.id_lote WHERE l.id_fornecedor = f.id_fornecedor)
+	FROM Fornecedor f WHERE f.id_fornecedor = $1;`
+
+// Conta o que seria removido em cascata pelo Delete, sem remover nada. Itens de venda
+// dos lotes do fornecedor são reportados em Bloqueios, pois fazem o Delete falhar.
+func (s *Store) DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.DeletePreview, error) { return s.deletePreview(ctx, id) })
+}
+
+func (s *Store) deletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	counts, err := util.CountCascade(s.db, ctx, deletePreviewQuery, id, "lote", "item_venda")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, types.ErrNotFound
+		}
+		return nil, err
+	}
+	preview := &model.DeletePreview{Entidade: "fornecedor", Id: id, Cascata: map[string]int64{"lote": counts["lote"]}}
+	if n := counts["item_venda"]; n > 0 {
+		preview.Bloqueios = map[string]int64{"item_venda": n}
+	}
+	return preview, nil
+}
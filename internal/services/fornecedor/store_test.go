@@ -0,0 +1,102 @@
+package fornecedor
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"edna/internal/util"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Driver mínimo que devolve `total` fornecedores e chama onNext após entregar cada linha
+type fakeDriver struct {
+	total  int
+	onNext func(sent int)
+	// Erro devolvido pela primeira consulta após a primeira linha, simulando uma falha na iteração
+	failFirst error
+	queries   int
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error)        { return &fakeConn{d}, nil }
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return &fakeConn{d}, nil }
+func (d *fakeDriver) Driver() driver.Driver                        { return d }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.queries++
+	return &fakeRows{d: c.d, query: c.d.queries}, nil
+}
+
+type fakeRows struct {
+	d     *fakeDriver
+	query int
+	sent  int
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id_fornecedor", "nome", "cnpj"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.sent == r.d.total {
+		return io.EOF
+	}
+	if r.d.failFirst != nil && r.query == 1 && r.sent == 1 {
+		return r.d.failFirst
+	}
+	dest[0], dest[1], dest[2] = int64(r.sent+1), "Fornecedor", "00000000000000"
+	r.sent++
+	if r.d.onNext != nil {
+		r.d.onNext(r.sent)
+	}
+	return nil
+}
+
+func TestGetAllStopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := &fakeDriver{total: 5}
+	d.onNext = func(sent int) {
+		if sent == 2 {
+			cancel()
+		}
+	}
+	db := sql.OpenDB(d)
+	defer db.Close()
+
+	fornecedores, err := NewStore(&util.DB{DB: db}).GetAll(ctx, util.Filter{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled; got %v (%d fornecedores)", err, len(fornecedores))
+	}
+	if fornecedores != nil {
+		t.Errorf("expected no partial result; got %d fornecedores", len(fornecedores))
+	}
+}
+
+func TestGetAllRetriesTransientIterationError(t *testing.T) {
+	d := &fakeDriver{total: 3, failFirst: &pgconn.PgError{Code: "40001"}}
+	db := sql.OpenDB(d)
+	defer db.Close()
+
+	fornecedores, err := NewStore(&util.DB{DB: db, ReadRetries: 2}).GetAll(context.Background(), util.Filter{})
+	if err != nil {
+		t.Fatalf("expected the serialization failure to be retried; got %v", err)
+	}
+	if len(fornecedores) != 3 {
+		t.Errorf("expected 3 fornecedores from the second attempt; got %d", len(fornecedores))
+	}
+	if d.queries != 2 {
+		t.Errorf("expected the query to run twice; got %d", d.queries)
+	}
+}
@@ -3,8 +3,8 @@ package funcionario
 import (
 	"context"
 	"edna/internal/model"
+	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -14,6 +14,7 @@ type Handler struct {
 
 type FuncionarioStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.Funcionario, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	Create(ctx context.Context, props *model.Funcionario) error
 	GetByID(ctx context.Context, id int64) (*model.Funcionario, error)
 	Update(ctx context.Context, props *model.Funcionario) error
@@ -24,8 +25,9 @@ func NewHandler(store FuncionarioStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /funcionarios", h.getAll)
+	mux.HandleFunc("GET /funcionarios/count", h.count)
 	mux.HandleFunc("POST /funcionarios", h.create)
 	mux.HandleFunc("GET /funcionarios/{id}", h.fetch)
 	mux.HandleFunc("PUT /funcionarios/{id}", h.update)
@@ -54,15 +56,49 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 	funcionarios, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	util.SetPaginationHeaders(w, r, filters, len(funcionarios), total)
+
 	err = util.WriteJSON(w, http.StatusOK, funcionarios)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// @Summary Count Funcionarios
+// @Description Returns how many rows match the filters accepted by GET /funcionarios, ignoring sort and pagination.
+// @Tags Funcionario
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /funcionarios/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewFuncionarioFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
 // @Summary Create Funcionario
 // @Tags Funcionario
 // @Accept json
@@ -82,16 +118,24 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.FuncionarioCreate
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := payload.Validate(); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToFuncionario()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -119,7 +163,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 
 	funcionario, err := h.store.GetByID(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 	if funcionario == nil {
@@ -154,17 +198,25 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.FuncionarioCreate
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := payload.Validate(); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToFuncionario()
 	model.Id = id
 	err = h.store.Update(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -191,7 +243,7 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 
 	model, err := h.store.Delete(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
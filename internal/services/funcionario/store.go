@@ -9,24 +9,32 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{db}
 }
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Funcionario, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Funcionario, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.Funcionario, error) {
 
 	query := "SELECT id_funcionario, nome, CPF, tipo, expediente, salario, data_contratacao FROM Funcionario AS fc"
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, &filter, "fc")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	funcionarios := make([]model.Funcionario, 0)
 
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var funcionario model.Funcionario
 		err = rows.Scan(&funcionario.Id, &funcionario.Nome, &funcionario.CPF, &funcionario.Tipo, &funcionario.Expediente, &funcionario.Salario, &funcionario.DataContratacao)
 		if err != nil {
@@ -34,17 +42,34 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Funcion
 		}
 		funcionarios = append(funcionarios, funcionario)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return funcionarios, nil
 }
 
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM Funcionario AS fc"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "fc")
+}
+
 func (s *Store) Create(ctx context.Context, props *model.Funcionario) error {
+	return s.db.RecordWrite(s.create(ctx, props))
+}
+
+func (s *Store) create(ctx context.Context, props *model.Funcionario) error {
 	query := "INSERT INTO Funcionario (nome, CPF, tipo, expediente, salario, data_contratacao) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id_funcionario"
 	res := s.db.QueryRowContext(ctx, query, props.Nome, props.CPF, props.Tipo, props.Expediente, props.Salario, props.DataContratacao)
 	return res.Scan(&props.Id)
 }
 
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.Funcionario, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.Funcionario, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.Funcionario, error) {
 	query := "SELECT id_funcionario, nome, CPF, tipo, expediente, salario, data_contratacao FROM Funcionario WHERE id_funcionario = $1;"
 
 	row := s.db.QueryRowContext(ctx, query, id)
@@ -62,6 +87,10 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.Funcionario, erro
 }
 
 func (s *Store) Update(ctx context.Context, props *model.Funcionario) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.Funcionario) error {
 	query := "UPDATE Funcionario SET nome = $1, CPF = $2, tipo = $3, expediente = $4, salario = $5, data_contratacao = $6 WHERE id_funcionario = $7;"
 
 	res, err := s.db.ExecContext(ctx, query, props.Nome, props.CPF, props.Tipo, props.Expediente, props.Salario, props.DataContratacao, props.Id)
@@ -79,6 +108,11 @@ func (s *Store) Update(ctx context.Context, props *model.Funcionario) error {
 }
 
 func (s *Store) Delete(ctx context.Context, id int64) (*model.Funcionario, error) {
+	result, err := s.delete(ctx, id)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id int64) (*model.Funcionario, error) {
 	query := "DELETE FROM Funcionario WHERE id_funcionario = $1 RETURNING id_funcionario, nome, CPF, tipo, expediente, salario, data_contratacao;"
 
 	var model model.Funcionario
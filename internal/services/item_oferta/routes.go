@@ -3,8 +3,8 @@ package item_oferta
 import (
 	"context"
 	"edna/internal/model"
+	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -14,6 +14,7 @@ type Handler struct {
 
 type ItemOfertaStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.ItemOferta, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	GetAllByItemID(ctx context.Context, id int64) ([]model.ItemOferta, error)
 	GetAllByOfertaID(ctx context.Context, id int64) ([]model.ItemOferta, error)
 	GetByComposedID(ctx context.Context, id_produto int64, id_oferta int64) (*model.ItemOferta, error)
@@ -26,8 +27,9 @@ func NewHandler(store ItemOfertaStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /item_ofertas", h.getAll)
+	mux.HandleFunc("GET /item_ofertas/count", h.count)
 	mux.HandleFunc("POST /item_ofertas", h.create)
 	mux.HandleFunc("GET /item_ofertas/{id_produto}/{id_oferta}", h.fetch)
 	mux.HandleFunc("PUT /item_ofertas/{id_produto}/{id_oferta}", h.update)
@@ -58,15 +60,49 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 	itemOfertas, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	util.SetPaginationHeaders(w, r, filters, len(itemOfertas), total)
+
 	err = util.WriteJSON(w, http.StatusOK, itemOfertas)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// @Summary Count Item Ofertas
+// @Description Returns how many rows match the filters accepted by GET /item_ofertas, ignoring sort and pagination.
+// @Tags Item Oferta
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /item_ofertas/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewItemOfertaFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
 // @Summary Get ItemOferta by Item ID
 // @Tags Item Oferta
 // @Produce json
@@ -88,7 +124,7 @@ func (h *Handler) getAllByItemID(w http.ResponseWriter, r *http.Request) {
 
 	itens, err := h.store.GetAllByItemID(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 	if itens == nil {
@@ -123,7 +159,7 @@ func (h *Handler) getAllByOfertaID(w http.ResponseWriter, r *http.Request) {
 
 	itens, err := h.store.GetAllByOfertaID(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 	if itens == nil {
@@ -143,7 +179,7 @@ func (h *Handler) getAllByOfertaID(w http.ResponseWriter, r *http.Request) {
 // @Success 201 {object} model.ItemOferta
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 422 {object} types.ErrorResponse
-// @Router /itemOfertas [post]
+// @Router /item_ofertas [post]
 func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -154,16 +190,20 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.ItemOfertaCreate
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToItemOferta()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -194,7 +234,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 	// Chame o novo método do store
 	itemOferta, err := h.store.GetByComposedID(ctx, id_produto, id_oferta)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 	if itemOferta == nil {
@@ -212,12 +252,13 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 // @Tags ItemOferta
 // @Accept json
 // @Produce json
-// @Param id path int true "ItemOferta ID"
+// @Param id_produto path int true "Produto ID"
+// @Param id_oferta path int true "Oferta ID"
 // @Param item body model.ItemOfertaCreate true "ItemOferta payload"
 // @Success 200 {object} model.ItemOferta
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 422 {object} types.ErrorResponse
-// @Router /item_ofertas/{id} [put]
+// @Router /item_ofertas/{id_produto}/{id_oferta} [put]
 func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -229,18 +270,22 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.ItemOfertaCreate
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToItemOferta()
 	model.IDProduto = id_produto
 	model.IDOferta = id_oferta
 	err = h.store.Update(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -270,7 +315,7 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 	// Chame o método Delete com os dois IDs
 	model, err := h.store.Delete(ctx, id_produto, id_oferta)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
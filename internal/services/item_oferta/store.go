@@ -9,14 +9,18 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{db}
 }
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.ItemOferta, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.ItemOferta, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.ItemOferta, error) {
 	query := "SELECT quantidade, id_produto, id_oferta FROM contem_item_oferta as io"
 
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, &filter, "io")
@@ -27,6 +31,9 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.ItemOfe
 
 	itensOferta := make([]model.ItemOferta, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var io model.ItemOferta
 		err = rows.Scan(&io.Quantidade, &io.IDProduto, &io.IDOferta)
 		if err != nil {
@@ -34,11 +41,24 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.ItemOfe
 		}
 		itensOferta = append(itensOferta, io)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return itensOferta, nil
 }
 
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM contem_item_oferta AS io"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "io")
+}
+
 // GetByComposedID busca uma entrada específica de ItemOferta pela sua chave primária composta.
 func (s *Store) GetByComposedID(ctx context.Context, id_produto int64, id_oferta int64) (*model.ItemOferta, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.ItemOferta, error) { return s.getByComposedID(ctx, id_produto, id_oferta) })
+}
+
+func (s *Store) getByComposedID(ctx context.Context, id_produto int64, id_oferta int64) (*model.ItemOferta, error) {
 	query := "SELECT quantidade, id_produto, id_oferta FROM contem_item_oferta WHERE id_produto = $1 AND id_oferta = $2"
 	row := s.db.QueryRowContext(ctx, query, id_produto, id_oferta)
 
@@ -55,6 +75,10 @@ func (s *Store) GetByComposedID(ctx context.Context, id_produto int64, id_oferta
 
 // GetAllByItemID busca todas as entradas de ItemOferta para um determinado produto.
 func (s *Store) GetAllByItemID(ctx context.Context, id_produto int64) ([]model.ItemOferta, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.ItemOferta, error) { return s.getAllByItemID(ctx, id_produto) })
+}
+
+func (s *Store) getAllByItemID(ctx context.Context, id_produto int64) ([]model.ItemOferta, error) {
 	query := "SELECT quantidade, id_produto, id_oferta FROM contem_item_oferta WHERE id_produto = $1"
 	rows, err := s.db.QueryContext(ctx, query, id_produto)
 	if err != nil {
@@ -64,6 +88,9 @@ func (s *Store) GetAllByItemID(ctx context.Context, id_produto int64) ([]model.I
 
 	itensOferta := make([]model.ItemOferta, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var io model.ItemOferta
 		err = rows.Scan(&io.Quantidade, &io.IDProduto, &io.IDOferta)
 		if err != nil {
@@ -71,11 +98,18 @@ func (s *Store) GetAllByItemID(ctx context.Context, id_produto int64) ([]model.I
 		}
 		itensOferta = append(itensOferta, io)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return itensOferta, nil
 }
 
 // GetAllByOfertaID busca todas as entradas de ItemOferta para uma determinada oferta.
 func (s *Store) GetAllByOfertaID(ctx context.Context, id_oferta int64) ([]model.ItemOferta, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.ItemOferta, error) { return s.getAllByOfertaID(ctx, id_oferta) })
+}
+
+func (s *Store) getAllByOfertaID(ctx context.Context, id_oferta int64) ([]model.ItemOferta, error) {
 	query := "SELECT quantidade, id_produto, id_oferta FROM contem_item_oferta WHERE id_oferta = $1"
 	rows, err := s.db.QueryContext(ctx, query, id_oferta)
 	if err != nil {
@@ -85,6 +119,9 @@ func (s *Store) GetAllByOfertaID(ctx context.Context, id_oferta int64) ([]model.
 
 	ofertas := make([]model.ItemOferta, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var io model.ItemOferta
 		err = rows.Scan(&io.Quantidade, &io.IDProduto, &io.IDOferta)
 		if err != nil {
@@ -92,16 +129,27 @@ func (s *Store) GetAllByOfertaID(ctx context.Context, id_oferta int64) ([]model.
 		}
 		ofertas = append(ofertas, io)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return ofertas, nil
 }
 
 func (s *Store) Create(ctx context.Context, props *model.ItemOferta) error {
+	return s.db.RecordWrite(s.create(ctx, props))
+}
+
+func (s *Store) create(ctx context.Context, props *model.ItemOferta) error {
 	query := "INSERT INTO contem_item_oferta (quantidade, id_produto, id_oferta) VALUES ($1, $2, $3);"
 	_, err := s.db.ExecContext(ctx, query, props.Quantidade, props.IDProduto, props.IDOferta)
 	return err
 }
 
 func (s *Store) Update(ctx context.Context, props *model.ItemOferta) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.ItemOferta) error {
 	query := "UPDATE contem_item_oferta SET quantidade = $1 WHERE id_produto = $2 AND id_oferta = $3"
 	res, err := s.db.ExecContext(ctx, query, props.Quantidade, props.IDProduto, props.IDOferta)
 	if err != nil {
@@ -119,6 +167,11 @@ func (s *Store) Update(ctx context.Context, props *model.ItemOferta) error {
 }
 
 func (s *Store) Delete(ctx context.Context, id_produto int64, id_oferta int64) (*model.ItemOferta, error) {
+	result, err := s.delete(ctx, id_produto, id_oferta)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id_produto int64, id_oferta int64) (*model.ItemOferta, error) {
 	item, err := s.GetByComposedID(ctx, id_produto, id_oferta)
 	if err != nil {
 		return nil, err
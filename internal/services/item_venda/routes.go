@@ -5,7 +5,6 @@ import (
 	"edna/internal/model"
 	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -15,6 +14,7 @@ type Handler struct {
 
 type ItemVendaStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.ItemVenda, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	Create(ctx context.Context, props *model.ItemVenda) error
 	GetByID(ctx context.Context, id int64) (*model.ItemVenda, error)
 	Update(ctx context.Context, props *model.ItemVenda) error
@@ -25,14 +25,28 @@ func NewHandler(store ItemVendaStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /item_venda", h.getAll)
+	mux.HandleFunc("GET /item_venda/count", h.count)
 	mux.HandleFunc("POST /item_venda", h.create)
 	mux.HandleFunc("GET /item_venda/{id}", h.fetch)
 	mux.HandleFunc("PUT /item_venda/{id}", h.update)
 	mux.HandleFunc("DELETE /item_venda/{id}", h.delete)
 }
 
+// @Summary List ItemVenda
+// @Tags ItemVenda
+// @Produce json
+// @Param filter-id_venda query int false "Filter by id_venda using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)"
+// @Param filter-id_produto query int false "Filter by id_produto using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)"
+// @Param filter-quantidade query int false "Filter by quantidade using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)"
+// @Param filter-valor_unitario query number false "Filter by valor_unitario using operators: eq, ne, gt, lt, gte, lte. Format: operator.value (e.g. eq.1)"
+// @Param sort query string false "Sort fields: id_venda, id_produto, quantidade, valor_unitario. Prefix with '-' for desc. Comma separated for multiple fields"
+// @Param offset query int false "Pagination offset (default 0)"
+// @Param limit query int false "Pagination limit (default 10)"
+// @Success 200 {array} model.ItemVenda
+// @Failure 500 {object} types.ErrorResponse
+// @Router /item_venda [get]
 func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -44,15 +58,58 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 	itensVenda, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	util.SetPaginationHeaders(w, r, filters, len(itensVenda), total)
+
 	err = util.WriteJSON(w, http.StatusOK, itensVenda)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// @Summary Count ItemVenda
+// @Description Returns how many rows match the filters accepted by GET /item_venda, ignoring sort and pagination.
+// @Tags ItemVenda
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /item_venda/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewItemVendaFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
+// @Summary Create ItemVenda
+// @Tags ItemVenda
+// @Accept json
+// @Produce json
+// @Param payload body model.ItemVendaCreate true "ItemVenda payload"
+// @Success 201 {object} model.ItemVenda
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse
+// @Router /item_venda [post]
 func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -63,22 +120,35 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.ItemVendaCreate
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToItemVenda()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
 	util.WriteJSON(w, http.StatusCreated, model)
 }
 
+// @Summary Get ItemVenda by ID
+// @Tags ItemVenda
+// @Produce json
+// @Param id path int true "ItemVenda ID"
+// @Success 200 {object} model.ItemVenda
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /item_venda/{id} [get]
 func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -95,7 +165,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "ItemVenda not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -105,6 +175,17 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// @Summary Update ItemVenda
+// @Tags ItemVenda
+// @Accept json
+// @Produce json
+// @Param id path int true "ItemVenda ID"
+// @Param payload body model.ItemVendaCreate true "ItemVenda payload"
+// @Success 200 {object} model.ItemVenda
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse
+// @Router /item_venda/{id} [put]
 func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -116,11 +197,15 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.ItemVendaCreate
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToItemVenda()
 	model.IDItemVenda = id
@@ -130,13 +215,22 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "ItemVenda not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
 	util.WriteJSON(w, http.StatusOK, model)
 }
 
+// @Summary Delete ItemVenda
+// @Tags ItemVenda
+// @Produce json
+// @Param id path int true "ItemVenda ID"
+// @Success 200 {object} model.ItemVenda
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse
+// @Router /item_venda/{id} [delete]
 func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -153,7 +247,7 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "ItemVenda not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -10,15 +10,21 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
+	// Fuso da aplicação, usado para decidir se um lote já venceu
+	loc *time.Location
 }
 
-func NewStore(db *sql.DB) *Store {
-	return &Store{db}
+func NewStore(db *util.DB, loc *time.Location) *Store {
+	return &Store{db, loc}
 }
 
 // Encontra um ID de Lote adequado para um produto.
 func (s *Store) FindAvailableLote(ctx context.Context, idProduto int64, quantidade int64) (int64, error) {
+	return util.RetryRead(ctx, s.db, func() (int64, error) { return s.findAvailableLote(ctx, idProduto, quantidade) })
+}
+
+func (s *Store) findAvailableLote(ctx context.Context, idProduto int64, quantidade int64) (int64, error) {
 	query := `
 		SELECT
 			l.id_lote
@@ -32,7 +38,8 @@ func (s *Store) FindAvailableLote(ctx context.Context, idProduto int64, quantida
 		) iv ON l.id_lote = iv.id_lote
 		WHERE
 			l.id_produto = $1
-			AND (l.validade IS NULL OR l.validade > CURRENT_DATE)
+			-- Compara com o dia de hoje no fuso da aplicação, não no do banco
+			AND (l.validade IS NULL OR l.validade > $3)
 			-- Calcula o estoque restante e verifica se é suficiente
 			AND (l.quantidade_inicial - l.estragados - COALESCE(iv.total_vendido, 0)) >= $2
 		ORDER BY
@@ -40,7 +47,7 @@ func (s *Store) FindAvailableLote(ctx context.Context, idProduto int64, quantida
 		LIMIT 1;
 	`
 	var idLote int64
-	err := s.db.QueryRowContext(ctx, query, idProduto, quantidade).Scan(&idLote)
+	err := s.db.QueryRowContext(ctx, query, idProduto, quantidade, util.Today(time.Now(), s.loc).Format(util.DateLayout)).Scan(&idLote)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return 0, types.ErrNotFound
@@ -55,11 +62,15 @@ type ItemVendaDetail struct {
 	model.ItemVenda
 	NomeProduto string     `json:"nome_produto"`
 	Marca       string     `json:"marca"`
-	Validade    *time.Time `json:"validade"`
+	Validade    *model.Date `json:"validade"`
 }
 
 // Busca todos os itens de uma venda específica com detalhes do produto.
 func (s *Store) GetItemsByVendaID(ctx context.Context, idVenda int64) ([]ItemVendaDetail, error) {
+	return util.RetryRead(ctx, s.db, func() ([]ItemVendaDetail, error) { return s.getItemsByVendaID(ctx, idVenda) })
+}
+
+func (s *Store) getItemsByVendaID(ctx context.Context, idVenda int64) ([]ItemVendaDetail, error) {
 	query := `
 		SELECT
 			iv.id_item_venda, iv.id_venda, iv.id_lote, iv.quantidade, iv.valor_unitario,
@@ -81,6 +92,9 @@ func (s *Store) GetItemsByVendaID(ctx context.Context, idVenda int64) ([]ItemVen
 
 	var items []ItemVendaDetail
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var i ItemVendaDetail
 		err := rows.Scan(
 			&i.IDItemVenda, &i.IDVenda, &i.IDLote, &i.Quantidade, &i.ValorUnitario,
@@ -91,19 +105,30 @@ func (s *Store) GetItemsByVendaID(ctx context.Context, idVenda int64) ([]ItemVen
 		}
 		items = append(items, i)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.ItemVenda, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.ItemVenda, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.ItemVenda, error) {
 	query := "SELECT id_item_venda, id_venda, id_lote, quantidade, valor_unitario FROM item_venda AS IV"
 
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, &filter, "IV")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	itensVenda := make([]model.ItemVenda, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var iv model.ItemVenda
 		err = rows.Scan(&iv.IDItemVenda, &iv.IDVenda, &iv.IDLote, &iv.Quantidade, &iv.ValorUnitario)
 		if err != nil {
@@ -111,10 +136,23 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.ItemVen
 		}
 		itensVenda = append(itensVenda, iv)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return itensVenda, nil
 }
 
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM item_venda AS IV"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "IV")
+}
+
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.ItemVenda, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.ItemVenda, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.ItemVenda, error) {
 	query := "SELECT id_item_venda, id_venda, id_lote, quantidade, valor_unitario FROM item_venda WHERE id_item_venda = $1;"
 	row := s.db.QueryRowContext(ctx, query, id)
 
@@ -130,12 +168,20 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.ItemVenda, error)
 }
 
 func (s *Store) Create(ctx context.Context, props *model.ItemVenda) error {
+	return s.db.RecordWrite(s.create(ctx, props))
+}
+
+func (s *Store) create(ctx context.Context, props *model.ItemVenda) error {
 	query := "INSERT INTO item_venda (id_venda, id_lote, quantidade, valor_unitario) VALUES ($1, $2, $3, $4) RETURNING id_item_venda;"
 	res := s.db.QueryRowContext(ctx, query, props.IDVenda, props.IDLote, props.Quantidade, props.ValorUnitario)
 	return res.Scan(&props.IDItemVenda)
 }
 
 func (s *Store) Update(ctx context.Context, props *model.ItemVenda) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.ItemVenda) error {
 	query := "UPDATE item_venda SET id_venda = $1, id_lote = $2, quantidade = $3, valor_unitario = $4 WHERE id_item_venda = $5;"
 	res, err := s.db.ExecContext(ctx, query, props.IDVenda, props.IDLote, props.Quantidade, props.ValorUnitario, props.IDItemVenda)
 	if err != nil {
@@ -152,6 +198,11 @@ func (s *Store) Update(ctx context.Context, props *model.ItemVenda) error {
 }
 
 func (s *Store) Delete(ctx context.Context, id int64) (*model.ItemVenda, error) {
+	result, err := s.delete(ctx, id)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id int64) (*model.ItemVenda, error) {
 	query := "DELETE FROM item_venda WHERE id_item_venda = $1 RETURNING id_item_venda, id_venda, id_lote, quantidade, valor_unitario;"
 	var iv model.ItemVenda
 	row := s.db.QueryRowContext(ctx, query, id)
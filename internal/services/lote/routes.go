@@ -5,7 +5,6 @@ import (
 	"edna/internal/model"
 	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -21,20 +20,23 @@ type GastoMensal struct {
 
 type LoteStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.Lote, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	GetRelatorio(ctx context.Context) (map[uint]GastoMensal, error)
 	GetAllByIDProduto(ctx context.Context, id int64) ([]model.Lote, error)
 	Create(ctx context.Context, props *model.Lote) error
 	GetByID(ctx context.Context, id int64) (*model.Lote, error)
 	Update(ctx context.Context, props *model.Lote) error
 	Delete(ctx context.Context, id int64) (*model.Lote, error)
+	DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error)
 }
 
 func NewHandler(store LoteStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /lotes", h.getAll)
+	mux.HandleFunc("GET /lotes/count", h.count)
 	mux.HandleFunc("GET /lotes/produtos/{id}", h.getAllByIDProduto)
 	mux.HandleFunc("GET /lotes/relatorio", h.getRelatorio)
 	mux.HandleFunc("POST /lotes", h.create)
@@ -65,15 +67,49 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 	lotes, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	util.SetPaginationHeaders(w, r, filters, len(lotes), total)
+
 	err = util.WriteJSON(w, http.StatusOK, lotes)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// @Summary Count Lotes
+// @Description Returns how many rows match the filters accepted by GET /lotes, ignoring sort and pagination.
+// @Tags Lote
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /lotes/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewLoteFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
 // @Summary Create Lote
 // @Tags Lote
 // @Accept json
@@ -93,16 +129,20 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.LoteCreate
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToLote()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -134,7 +174,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Lote not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -165,11 +205,15 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.LoteCreate
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToLote()
 	model.Id = id
@@ -179,7 +223,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Lote not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -190,6 +234,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 // @Tags Lote
 // @Produce json
 // @Param id path int true "Lote ID"
+// @Param dry_run query bool false "Only report what would be deleted, returning a model.DeletePreview. Sold items block the delete and are listed in bloqueios"
 // @Success 200 {object} model.Lote
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 422 {object} types.ErrorResponse
@@ -204,13 +249,27 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if util.IsDryRun(r) {
+		preview, err := h.store.DeletePreview(ctx, id)
+		if err != nil {
+			if err == types.ErrNotFound {
+				util.ErrorJSON(w, "Lote not found.", http.StatusNotFound)
+				return
+			}
+			util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		util.WriteJSON(w, http.StatusOK, preview)
+		return
+	}
+
 	model, err := h.store.Delete(ctx, id)
 	if err != nil {
 		if err == types.ErrNotFound {
 			util.ErrorJSON(w, "Lote not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -230,7 +289,7 @@ func (h *Handler) getRelatorio(w http.ResponseWriter, r *http.Request) {
 
 	model, err := h.store.GetRelatorio(ctx)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -261,7 +320,7 @@ func (h *Handler) getAllByIDProduto(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Lote not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -9,22 +9,30 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{db}
 }
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Lote, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Lote, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.Lote, error) {
 	query := "SELECT id_lote, id_fornecedor, id_produto, data_fornecimento, validade, preco_unitario, estragados, quantidade_inicial FROM Lote AS l"
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, &filter, "l")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	lotes := make([]model.Lote, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var l model.Lote
 		err = rows.Scan(&l.Id, &l.IdFornecedor, &l.IdProduto, &l.DataFornecimento, &l.Validade, &l.PrecoUnitario, &l.Estragados, &l.QuantidadeInicial)
 		if err != nil {
@@ -32,10 +40,23 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Lote, e
 		}
 		lotes = append(lotes, l)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return lotes, nil
 }
 
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM Lote AS l"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "l")
+}
+
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.Lote, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.Lote, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.Lote, error) {
 	query := "SELECT id_lote, id_fornecedor, id_produto, data_fornecimento, validade, preco_unitario, estragados, quantidade_inicial FROM Lote WHERE id_lote = $1;"
 	row := s.db.QueryRowContext(ctx, query, id)
 
@@ -51,6 +72,10 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.Lote, error) {
 }
 
 func (s *Store) GetAllByIDProduto(ctx context.Context, id int64) ([]model.Lote, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Lote, error) { return s.getAllByIDProduto(ctx, id) })
+}
+
+func (s *Store) getAllByIDProduto(ctx context.Context, id int64) ([]model.Lote, error) {
 	query := "SELECT * FROM Lote WHERE id_produto = $1"
 	row, err := s.db.QueryContext(ctx, query, id)
 	if err != nil {
@@ -72,6 +97,10 @@ func (s *Store) GetAllByIDProduto(ctx context.Context, id int64) ([]model.Lote,
 }
 
 func (s *Store) Create(ctx context.Context, props *model.Lote) error {
+	return s.db.RecordWrite(s.create(ctx, props))
+}
+
+func (s *Store) create(ctx context.Context, props *model.Lote) error {
 	query := `
 		INSERT INTO Lote (id_fornecedor, id_produto, data_fornecimento, validade, preco_unitario, estragados, quantidade_inicial)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
@@ -81,6 +110,10 @@ func (s *Store) Create(ctx context.Context, props *model.Lote) error {
 }
 
 func (s *Store) Update(ctx context.Context, props *model.Lote) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.Lote) error {
 	query := `
 		UPDATE Lote SET
 		id_fornecedor = $1, id_produto = $2, data_fornecimento = $3, validade = $4,
@@ -100,7 +133,37 @@ func (s *Store) Update(ctx context.Context, props *model.Lote) error {
 	return nil
 }
 
+const deletePreviewQuery = `SELECT
+		(SELECT COUNT(*) FROM item_venda WHERE id_lote = l.id_lote)
+	FROM Lote l WHERE l.id_lote = $1;`
+
+// Conta o que seria removido pelo Delete, sem remover nada. Nada é apagado em cascata;
+// itens de venda do lote são reportados em Bloqueios, pois fazem o Delete falhar.
+func (s *Store) DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.DeletePreview, error) { return s.deletePreview(ctx, id) })
+}
+
+func (s *Store) deletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	counts, err := util.CountCascade(s.db, ctx, deletePreviewQuery, id, "item_venda")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, types.ErrNotFound
+		}
+		return nil, err
+	}
+	preview := &model.DeletePreview{Entidade: "lote", Id: id, Cascata: map[string]int64{}}
+	if n := counts["item_venda"]; n > 0 {
+		preview.Bloqueios = map[string]int64{"item_venda": n}
+	}
+	return preview, nil
+}
+
 func (s *Store) Delete(ctx context.Context, id int64) (*model.Lote, error) {
+	result, err := s.delete(ctx, id)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id int64) (*model.Lote, error) {
 	query := "DELETE FROM Lote WHERE id_lote = $1 RETURNING id_lote, id_fornecedor, id_produto, data_fornecimento, validade, preco_unitario, estragados, quantidade_inicial;"
 	var l model.Lote
 	row := s.db.QueryRowContext(ctx, query, id)
@@ -115,6 +178,10 @@ func (s *Store) Delete(ctx context.Context, id int64) (*model.Lote, error) {
 }
 
 func (s *Store) GetRelatorio(ctx context.Context) (map[uint]GastoMensal, error) {
+	return util.RetryRead(ctx, s.db, func() (map[uint]GastoMensal, error) { return s.getRelatorio(ctx) })
+}
+
+func (s *Store) getRelatorio(ctx context.Context) (map[uint]GastoMensal, error) {
 	query := `
 		SELECT
 			EXTRACT(YEAR FROM data_fornecimento)::int AS ano,
@@ -133,6 +200,9 @@ func (s *Store) GetRelatorio(ctx context.Context) (map[uint]GastoMensal, error)
 
 	gastos := make(map[uint]GastoMensal)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var ano uint
 		var g GastoMensal
 
@@ -5,7 +5,6 @@ import (
 	"edna/internal/model"
 	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -15,18 +14,21 @@ type Handler struct {
 
 type OfertaStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.Oferta, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	Create(ctx context.Context, props *model.Oferta) error
 	GetByID(ctx context.Context, id int64) (*model.Oferta, error)
 	Update(ctx context.Context, props *model.Oferta) error
 	Delete(ctx context.Context, id int64) (*model.Oferta, error)
+	DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error)
 }
 
 func NewHandler(store OfertaStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /ofertas", h.getAll)
+	mux.HandleFunc("GET /ofertas/count", h.count)
 	mux.HandleFunc("POST /ofertas", h.create)
 	mux.HandleFunc("GET /ofertas/{id}", h.fetch)
 	mux.HandleFunc("PUT /ofertas/{id}", h.update)
@@ -55,15 +57,49 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 	ofertas, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	util.SetPaginationHeaders(w, r, filters, len(ofertas), total)
+
 	err = util.WriteJSON(w, http.StatusOK, ofertas)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// @Summary Count Ofertas
+// @Description Returns how many rows match the filters accepted by GET /ofertas, ignoring sort and pagination.
+// @Tags Oferta
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /ofertas/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewOfertaFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
 // @Summary Create Oferta
 // @Tags Oferta
 // @Accept json
@@ -83,16 +119,20 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.OfertaCreate
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToOferta()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -124,7 +164,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Oferta not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -155,11 +195,15 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.OfertaCreate
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToOferta()
 	model.Id = id
@@ -169,7 +213,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 			util.ErrorJSON(w, "Oferta not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -180,6 +224,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 // @Tags Oferta
 // @Produce json
 // @Param id path int true "Oferta ID"
+// @Param dry_run query bool false "Only report what would be deleted in cascade, returning a model.DeletePreview"
 // @Success 200 {object} model.Oferta
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 422 {object} types.ErrorResponse
@@ -194,13 +239,27 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if util.IsDryRun(r) {
+		preview, err := h.store.DeletePreview(ctx, id)
+		if err != nil {
+			if err == types.ErrNotFound {
+				util.ErrorJSON(w, "Oferta not found.", http.StatusNotFound)
+				return
+			}
+			util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		util.WriteJSON(w, http.StatusOK, preview)
+		return
+	}
+
 	model, err := h.store.Delete(ctx, id)
 	if err != nil {
 		if err == types.ErrNotFound {
 			util.ErrorJSON(w, "Oferta not found.", http.StatusNotFound)
 			return
 		}
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
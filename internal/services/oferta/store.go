@@ -9,22 +9,30 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{db}
 }
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Oferta, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Oferta, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.Oferta, error) {
 	query := "SELECT id_oferta, nome, data_criacao, data_inicio, data_fim, valor_fixo, percentual_desconto FROM Oferta AS o"
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, &filter, "o")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	ofertas := make([]model.Oferta, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var o model.Oferta
 		err = rows.Scan(&o.Id, &o.Nome, &o.DataCriacao, &o.DataInicio, &o.DataFim, &o.ValorFixo, &o.PercentualDesconto)
 		if err != nil {
@@ -32,10 +40,23 @@ func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Oferta,
 		}
 		ofertas = append(ofertas, o)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return ofertas, nil
 }
 
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM Oferta AS o"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "o")
+}
+
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.Oferta, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.Oferta, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.Oferta, error) {
 	query := "SELECT id_oferta, nome, data_criacao, data_inicio, data_fim, valor_fixo, percentual_desconto FROM Oferta WHERE id_oferta = $1;"
 	row := s.db.QueryRowContext(ctx, query, id)
 
@@ -51,6 +72,10 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.Oferta, error) {
 }
 
 func (s *Store) Create(ctx context.Context, props *model.Oferta) error {
+	return s.db.RecordWrite(s.create(ctx, props))
+}
+
+func (s *Store) create(ctx context.Context, props *model.Oferta) error {
 	query := `
 		INSERT INTO Oferta (nome, data_inicio, data_fim, valor_fixo, percentual_desconto) 
 		VALUES ($1, $2, $3, $4, $5) 
@@ -61,6 +86,10 @@ func (s *Store) Create(ctx context.Context, props *model.Oferta) error {
 }
 
 func (s *Store) Update(ctx context.Context, props *model.Oferta) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.Oferta) error {
 	query := `
 		UPDATE Oferta SET 
 		nome = $1, data_inicio = $2, data_fim = $3, valor_fixo = $4, percentual_desconto = $5
@@ -80,6 +109,11 @@ func (s *Store) Update(ctx context.Context, props *model.Oferta) error {
 }
 
 func (s *Store) Delete(ctx context.Context, id int64) (*model.Oferta, error) {
+	result, err := s.delete(ctx, id)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id int64) (*model.Oferta, error) {
 	query := "DELETE FROM Oferta WHERE id_oferta = $1 RETURNING id_oferta, nome, data_criacao, data_inicio, data_fim, valor_fixo, percentual_desconto;"
 	var o model.Oferta
 	row := s.db.QueryRowContext(ctx, query, id)
@@ -92,3 +126,25 @@ func (s *Store) Delete(ctx context.Context, id int64) (*model.Oferta, error) {
 	}
 	return &o, nil
 }
+
+// Linhas removidas em cascata junto com a oferta, na ordem das tabelas passadas a CountCascade
+const deletePreviewQuery = `SELECT
+		(SELECT COUNT(*) FROM contem_item_oferta WHERE id_oferta = o.id_oferta),
+		(SELECT COUNT(*) FROM aplica_oferta WHERE id_oferta = o.id_oferta)
+	FROM Oferta o WHERE o.id_oferta = $1;`
+
+// Conta o que seria removido em cascata pelo Delete, sem remover nada.
+func (s *Store) DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.DeletePreview, error) { return s.deletePreview(ctx, id) })
+}
+
+func (s *Store) deletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	cascata, err := util.CountCascade(s.db, ctx, deletePreviewQuery, id, "contem_item_oferta", "aplica_oferta")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, types.ErrNotFound
+		}
+		return nil, err
+	}
+	return &model.DeletePreview{Entidade: "oferta", Id: id, Cascata: cascata}, nil
+}
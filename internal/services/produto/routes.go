@@ -5,6 +5,9 @@ import (
 	"edna/internal/model"
 	"edna/internal/types"
 	"edna/internal/util"
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 )
 
@@ -14,8 +17,12 @@ type Handler struct {
 
 type ProdutoStore interface {
 	GetAll(ctx context.Context, filter *util.Filter) ([]model.UnionProduto, error)
+	Count(ctx context.Context, filter *util.Filter) (int64, error)
+	Stream(ctx context.Context, filter *util.Filter, fn func(model.UnionProduto) error) error
 	GetAllComercial(ctx context.Context, filter *util.Filter) ([]model.Comercial, error)
+	CountComercial(ctx context.Context, filter *util.Filter) (int64, error)
 	GetAllEstrutural(ctx context.Context, filter *util.Filter) ([]model.Produto, error)
+	CountEstrutural(ctx context.Context, filter *util.Filter) (int64, error)
 	CreateComercial(ctx context.Context, props *model.Comercial) error
 	Create(ctx context.Context, props *model.Produto) error
 	UpdateComercial(ctx context.Context, props *model.Comercial) error
@@ -24,14 +31,17 @@ type ProdutoStore interface {
 	GetByID(ctx context.Context, id int64) (*model.Produto, error)
 	GetQntByID(ctx context.Context, id int64) (*model.ProdutoWithQnt, error)
 	Delete(ctx context.Context, id int64) error
+	DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error)
 }
 
 func NewHandler(store ProdutoStore) Handler {
 	return Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /produtos", h.getAll)
+	mux.HandleFunc("GET /produtos/count", h.count)
+	mux.Handle("GET /produtos/stream", util.Produces(h.streamHandler, "application/x-ndjson"))
 	mux.HandleFunc("POST /produtos", h.createEstruturalHandler)
 	mux.HandleFunc("GET /produtos/{id}", h.getEstruturalHandler)
 	mux.HandleFunc("PUT /produtos/{id}", h.updateEstruturalHandler)
@@ -72,13 +82,101 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 
 	produtos, err := h.store.GetAll(ctx, &filter)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	total, err := h.store.Count(ctx, &filter)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	util.SetPaginationHeaders(w, r, filter, len(produtos), total)
 
 	util.WriteJSON(w, http.StatusOK, produtos)
 }
 
+// @Summary Count Produtos
+// @Description Returns how many rows match the filters accepted by GET /produtos, ignoring sort and pagination.
+// @Tags Produtos
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /produtos/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewProdutoFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, &filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
+// @Summary Stream Produtos (all types)
+// @Description Exports every product as newline-delimited JSON (one object per line), without buffering the whole result.
+// @Tags Produtos
+// @Produce application/x-ndjson
+// @Param filter-nome query string false "Filter by nome. Format: <op>.<value>. Ops: like, ilike, eq, ne"
+// @Param filter-categoria query string false "Filter by categoria. Format: <op>.<value>. Ops: like, ilike, eq, ne"
+// @Param filter-marca query string false "Filter by marca. Format: <op>.<value>. Ops: like, ilike, eq, ne"
+// @Param sort query string false "Sort by attribute. Allowed: nome, categoria, marca. Prefix '-' for desc. Comma separated"
+// @Success 200 {object} model.UnionProduto
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /produtos/stream [get]
+func (h *Handler) streamHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := NewProdutoFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Sem util.RequestTimeout: exportações podem demorar mais que uma listagem comum
+	rc := http.NewResponseController(w)
+	enc := json.NewEncoder(w)
+	written := false
+	err = h.store.Stream(r.Context(), &filter, func(p model.UnionProduto) error {
+		// Para assim que o cliente desconectar, liberando a conexão com o banco
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		if !written {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			written = true
+		}
+		if err := enc.Encode(p); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+	if err != nil {
+		if !written {
+			util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		// O status já foi enviado, resta apenas registrar o erro
+		if !errors.Is(err, context.Canceled) {
+			log.Printf("Error streaming produtos: %v", err)
+		}
+		return
+	}
+	if !written {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
 // @Summary List Comercial products
 // @Tags Produtos
 // @Produce json
@@ -103,9 +201,15 @@ func (h *Handler) getAllComercialHandler(w http.ResponseWriter, r *http.Request)
 	}
 	produtos, err := h.store.GetAllComercial(ctx, &filter)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	total, err := h.store.CountComercial(ctx, &filter)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	util.SetPaginationHeaders(w, r, filter, len(produtos), total)
 
 	if err = util.WriteJSON(w, http.StatusOK, produtos); err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
@@ -136,9 +240,15 @@ func (h *Handler) getAllEstruturalHandler(w http.ResponseWriter, r *http.Request
 
 	produtos, err := h.store.GetAllEstrutural(ctx, &filter)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	total, err := h.store.CountEstrutural(ctx, &filter)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	util.SetPaginationHeaders(w, r, filter, len(produtos), total)
 
 	if err = util.WriteJSON(w, http.StatusOK, produtos); err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
@@ -152,6 +262,7 @@ func (h *Handler) getAllEstruturalHandler(w http.ResponseWriter, r *http.Request
 // @Param produto body model.ComercialCreate true "Comercial product payload"
 // @Success 201 {object} model.Comercial
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /produtos/comercial [post]
 func (h *Handler) createComercialHandler(w http.ResponseWriter, r *http.Request) {
@@ -160,17 +271,17 @@ func (h *Handler) createComercialHandler(w http.ResponseWriter, r *http.Request)
 
 	payload := model.ComercialCreate{}
 	if err := util.ReadJSON(r, &payload); err != nil {
-		util.ErrorJSON(w, "Failed to decode request body", http.StatusBadRequest)
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	produto := payload.ToComercial()
 	if err := h.store.CreateComercial(ctx, &produto); err != nil {
-		status := http.StatusInternalServerError
-		if err == types.ErrNotFound {
-			status = http.StatusNotFound
-		}
-		util.ErrorJSON(w, err.Error(), status)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -186,6 +297,7 @@ func (h *Handler) createComercialHandler(w http.ResponseWriter, r *http.Request)
 // @Param produto body model.ProdutoCreate true "Product payload"
 // @Success 201 {object} model.Produto
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /produtos [post]
 func (h *Handler) createEstruturalHandler(w http.ResponseWriter, r *http.Request) {
@@ -194,13 +306,17 @@ func (h *Handler) createEstruturalHandler(w http.ResponseWriter, r *http.Request
 
 	payload := model.ProdutoCreate{}
 	if err := util.ReadJSON(r, &payload); err != nil {
-		util.ErrorJSON(w, "Failed to decode request body", http.StatusBadRequest)
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	produto := payload.ToProduto()
 	if err := h.store.Create(ctx, &produto); err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -217,6 +333,8 @@ func (h *Handler) createEstruturalHandler(w http.ResponseWriter, r *http.Request
 // @Param produto body model.ComercialCreate true "Comercial product payload"
 // @Success 200 {object} model.Comercial
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /produtos/comercial/{id} [put]
 func (h *Handler) updateComercialHandler(w http.ResponseWriter, r *http.Request) {
@@ -231,14 +349,18 @@ func (h *Handler) updateComercialHandler(w http.ResponseWriter, r *http.Request)
 
 	payload := model.ComercialCreate{}
 	if err := util.ReadJSON(r, &payload); err != nil {
-		util.ErrorJSON(w, "Failed to decode request body", http.StatusBadRequest)
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	produto := payload.ToComercial()
 	produto.Id = id
 	if err := h.store.UpdateComercial(ctx, &produto); err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -255,6 +377,8 @@ func (h *Handler) updateComercialHandler(w http.ResponseWriter, r *http.Request)
 // @Param produto body model.ProdutoCreate true "Product payload"
 // @Success 200 {object} model.Produto
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /produtos/{id} [put]
 func (h *Handler) updateEstruturalHandler(w http.ResponseWriter, r *http.Request) {
@@ -269,14 +393,18 @@ func (h *Handler) updateEstruturalHandler(w http.ResponseWriter, r *http.Request
 
 	payload := model.ProdutoCreate{}
 	if err := util.ReadJSON(r, &payload); err != nil {
-		util.ErrorJSON(w, "Failed to decode request body", http.StatusBadRequest)
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	produto := payload.ToProduto()
 	produto.Id = id
 	if err := h.store.Update(ctx, &produto); err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -291,6 +419,7 @@ func (h *Handler) updateEstruturalHandler(w http.ResponseWriter, r *http.Request
 // @Param id path int true "Produto ID"
 // @Success 200 {object} model.Comercial
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /produtos/comercial/{id} [get]
 func (h *Handler) getComercialHandler(w http.ResponseWriter, r *http.Request) {
@@ -305,7 +434,7 @@ func (h *Handler) getComercialHandler(w http.ResponseWriter, r *http.Request) {
 
 	produto, err := h.store.GetComercialByID(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -320,6 +449,7 @@ func (h *Handler) getComercialHandler(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Produto ID"
 // @Success 200 {object} model.Produto
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /produtos/{id} [get]
 func (h *Handler) getEstruturalHandler(w http.ResponseWriter, r *http.Request) {
@@ -334,7 +464,7 @@ func (h *Handler) getEstruturalHandler(w http.ResponseWriter, r *http.Request) {
 
 	produto, err := h.store.GetByID(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -346,8 +476,12 @@ func (h *Handler) getEstruturalHandler(w http.ResponseWriter, r *http.Request) {
 // @Summary Delete Produto
 // @Tags Produtos
 // @Param id path int true "Produto ID"
+// @Param dry_run query bool false "Only report what would be deleted in cascade, returning a model.DeletePreview. Sold lotes and ofertas containing the produto block the delete and are listed in bloqueios"
 // @Success 204 {string} string
+// @Success 200 {object} model.DeletePreview
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /produtos/{id} [delete]
 func (h *Handler) deleteProdutoHandler(w http.ResponseWriter, r *http.Request) {
@@ -360,8 +494,18 @@ func (h *Handler) deleteProdutoHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if util.IsDryRun(r) {
+		preview, err := h.store.DeletePreview(ctx, id)
+		if err != nil {
+			util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		util.WriteJSON(w, http.StatusOK, preview)
+		return
+	}
+
 	if err := h.store.Delete(ctx, id); err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -374,8 +518,9 @@ func (h *Handler) deleteProdutoHandler(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Produto ID"
 // @Success 200 {object} model.ProdutoWithQnt
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
-// @Router /produtos/{id}/quantidades [get]
+// @Router /produtos/quantidade/{id} [get]
 func (h *Handler) getQuantidadeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
 	defer cancel()
@@ -388,7 +533,7 @@ func (h *Handler) getQuantidadeHandler(w http.ResponseWriter, r *http.Request) {
 
 	model, err := h.store.GetQntByID(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -0,0 +1,178 @@
+package produto
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"edna/internal/model"
+	"edna/internal/util"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Implementa apenas os métodos usados nos testes, os demais entram em pânico
+type stubStore struct {
+	ProdutoStore
+	produtos []model.UnionProduto
+	// Chamado após cada produto entregue, com a quantidade entregue até então
+	afterEach func(sent int)
+	sent      int
+	// Erro retornado pelas operações de escrita e busca por ID
+	err error
+	// Total informado pelas contagens das listagens
+	total int64
+}
+
+func (s *stubStore) GetAllComercial(ctx context.Context, filter *util.Filter) ([]model.Comercial, error) {
+	return make([]model.Comercial, filter.Limit), nil
+}
+
+func (s *stubStore) CountComercial(ctx context.Context, filter *util.Filter) (int64, error) {
+	return s.total, nil
+}
+
+func (s *stubStore) GetAllEstrutural(ctx context.Context, filter *util.Filter) ([]model.Produto, error) {
+	return make([]model.Produto, filter.Limit), nil
+}
+
+func (s *stubStore) CountEstrutural(ctx context.Context, filter *util.Filter) (int64, error) {
+	return s.total, nil
+}
+
+func (s *stubStore) Create(ctx context.Context, props *model.Produto) error {
+	return s.err
+}
+
+func (s *stubStore) GetByID(ctx context.Context, id int64) (*model.Produto, error) {
+	return nil, s.err
+}
+
+func (s *stubStore) Stream(ctx context.Context, filter *util.Filter, fn func(model.UnionProduto) error) error {
+	for _, p := range s.produtos {
+		if err := fn(p); err != nil {
+			return err
+		}
+		s.sent++
+		if s.afterEach != nil {
+			s.afterEach(s.sent)
+		}
+	}
+	return nil
+}
+
+func TestStreamHandler(t *testing.T) {
+	preco := float32(12.5)
+	store := &stubStore{produtos: []model.UnionProduto{
+		{Produto: model.Produto{Id: 1, Nome: "Cerveja", Categoria: "Bebida", Marca: "Brahma"}, PrecoVenda: &preco},
+		{Produto: model.Produto{Id: 2, Nome: "Mesa", Categoria: "Mobilia", Marca: "Tok"}},
+		{Produto: model.Produto{Id: 3, Nome: "Copo", Categoria: "Utensilio", Marca: "Nadir"}},
+	}}
+	h := NewHandler(store)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/produtos/stream", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200; got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected content type application/x-ndjson; got %q", ct)
+	}
+
+	scanner := bufio.NewScanner(rec.Body)
+	var lines int
+	for scanner.Scan() {
+		var p model.UnionProduto
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", lines+1, err)
+		}
+		if p.Id != store.produtos[lines].Id {
+			t.Errorf("line %d: expected produto %d; got %d", lines+1, store.produtos[lines].Id, p.Id)
+		}
+		lines++
+	}
+	if lines != len(store.produtos) {
+		t.Errorf("expected %d lines; got %d", len(store.produtos), lines)
+	}
+}
+
+func TestStreamHandlerStopsOnClientDisconnect(t *testing.T) {
+	store := &stubStore{}
+	for i := range 100 {
+		store.produtos = append(store.produtos, model.UnionProduto{Produto: model.Produto{Id: int64(i + 1)}})
+	}
+	h := NewHandler(store)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store.afterEach = func(sent int) {
+		if sent == 2 {
+			cancel()
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/produtos/stream", nil).WithContext(ctx)
+	mux.ServeHTTP(rec, req)
+
+	if store.sent != 2 {
+		t.Errorf("expected iteration to stop after 2 produtos; sent %d", store.sent)
+	}
+}
+
+func TestStoreErrorsMapToStatus(t *testing.T) {
+	duplicate := &pgconn.PgError{Code: "23505", Detail: "Key (nome)=(Cerveja) already exists."}
+	for _, tc := range []struct {
+		name   string
+		err    error
+		req    *http.Request
+		status int
+	}{
+		{"duplicate produto", fmt.Errorf("failed to insert produto: %w", duplicate),
+			httptest.NewRequest(http.MethodPost, "/produtos", strings.NewReader(`{"nome":"Cerveja"}`)), http.StatusConflict},
+		{"missing produto", sql.ErrNoRows,
+			httptest.NewRequest(http.MethodGet, "/produtos/7", nil), http.StatusNotFound},
+	} {
+		h := NewHandler(&stubStore{err: tc.err})
+		mux := http.NewServeMux()
+		h.RegisterRoutes(mux)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, tc.req)
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected status %d; got %d (%s)", tc.name, tc.status, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestListsSetPaginationHeaders(t *testing.T) {
+	mux := http.NewServeMux()
+	h := NewHandler(&stubStore{total: 5})
+	h.RegisterRoutes(mux)
+
+	for _, path := range []string{"/produtos/comercial", "/produtos/estrutural"} {
+		r := httptest.NewRequest(http.MethodGet, path+"?limit=2", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200; got %d", path, rec.Code)
+		}
+		if got := rec.Header().Get("X-Total-Count"); got != "5" {
+			t.Errorf("%s: expected X-Total-Count 5; got %q", path, got)
+		}
+		if link := rec.Header().Get("Link"); !strings.Contains(link, `offset=2>; rel="next"`) {
+			t.Errorf("%s: expected a Link to the next page; got %q", path, link)
+		}
+	}
+}
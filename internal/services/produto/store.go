@@ -10,16 +10,20 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{
 		db: db,
 	}
 }
 
 func (s *Store) GetAll(ctx context.Context, filter *util.Filter) ([]model.UnionProduto, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.UnionProduto, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter *util.Filter) ([]model.UnionProduto, error) {
 	query := "SELECT p.id_produto, p.nome, p.categoria, p.marca, c.preco_venda FROM Produto p LEFT JOIN ProdutoComercial AS c using (id_produto)"
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, filter, "p")
 	if err != nil {
@@ -29,6 +33,9 @@ func (s *Store) GetAll(ctx context.Context, filter *util.Filter) ([]model.UnionP
 
 	produtos := make([]model.UnionProduto, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		c := model.UnionProduto{}
 		err = rows.Scan(&c.Id, &c.Nome, &c.Categoria, &c.Marca, &c.PrecoVenda)
 		if err != nil {
@@ -40,11 +47,49 @@ func (s *Store) GetAll(ctx context.Context, filter *util.Filter) ([]model.UnionP
 		}
 		produtos = append(produtos, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return produtos, nil
 }
 
+// Conta quantos produtos satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter *util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM Produto p LEFT JOIN ProdutoComercial AS c using (id_produto)"
+	return util.CountRowsWithFilter(s.db, ctx, query, filter, "p")
+}
+
+// Percorre os produtos que satisfazem o filtro chamando fn para cada um, sem carregar
+// o resultado inteiro em memória. A iteração para no primeiro erro retornado por fn.
+func (s *Store) Stream(ctx context.Context, filter *util.Filter, fn func(model.UnionProduto) error) error {
+	query := "SELECT p.id_produto, p.nome, p.categoria, p.marca, c.preco_venda FROM Produto p LEFT JOIN ProdutoComercial AS c using (id_produto)"
+	rows, err := util.StreamRowsWithFilter(s.db, ctx, query, filter, "p")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		c := model.UnionProduto{}
+		if err := rows.Scan(&c.Id, &c.Nome, &c.Categoria, &c.Marca, &c.PrecoVenda); err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (s *Store) GetAllComercial(ctx context.Context, filter *util.Filter) ([]model.Comercial, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Comercial, error) { return s.getAllComercial(ctx, filter) })
+}
+
+func (s *Store) getAllComercial(ctx context.Context, filter *util.Filter) ([]model.Comercial, error) {
 	query := `
 		SELECT p.id_produto, p.nome, p.categoria, p.marca, c.preco_venda
 		FROM Produto p
@@ -57,6 +102,9 @@ func (s *Store) GetAllComercial(ctx context.Context, filter *util.Filter) ([]mod
 
 	produtos := make([]model.Comercial, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		c := model.Comercial{}
 		err = rows.Scan(&c.Id, &c.Nome, &c.Categoria, &c.Marca, &c.PrecoVenda)
 		if err != nil {
@@ -68,11 +116,24 @@ func (s *Store) GetAllComercial(ctx context.Context, filter *util.Filter) ([]mod
 		}
 		produtos = append(produtos, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return produtos, nil
 }
 
+// Conta quantos produtos comerciais satisfazem o filtro, ignorando a paginação
+func (s *Store) CountComercial(ctx context.Context, filter *util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM Produto p INNER JOIN ProdutoComercial c ON p.id_produto = c.id_produto"
+	return util.CountRowsWithFilter(s.db, ctx, query, filter, "p")
+}
+
 func (s *Store) GetAllEstrutural(ctx context.Context, filter *util.Filter) ([]model.Produto, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Produto, error) { return s.getAllEstrutural(ctx, filter) })
+}
+
+func (s *Store) getAllEstrutural(ctx context.Context, filter *util.Filter) ([]model.Produto, error) {
 	query := `
 		SELECT p.id_produto, p.nome, p.categoria, p.marca
 		FROM Produto p
@@ -87,6 +148,9 @@ func (s *Store) GetAllEstrutural(ctx context.Context, filter *util.Filter) ([]mo
 
 	produtos := make([]model.Produto, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		c := model.Produto{}
 		err = rows.Scan(&c.Id, &c.Nome, &c.Categoria, &c.Marca)
 		if err != nil {
@@ -98,11 +162,32 @@ func (s *Store) GetAllEstrutural(ctx context.Context, filter *util.Filter) ([]mo
 		}
 		produtos = append(produtos, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return produtos, nil
 }
 
+// Conta quantos produtos estruturais satisfazem o filtro, ignorando a paginação. Os
+// produtos sem ProdutoComercial ficam em uma subconsulta para que o WHERE do filtro
+// não se junte ao `c.id_produto IS NULL`.
+func (s *Store) CountEstrutural(ctx context.Context, filter *util.Filter) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM (
+			SELECT p.id_produto, p.nome, p.categoria, p.marca
+			FROM Produto p
+			LEFT JOIN ProdutoComercial c ON p.id_produto = c.id_produto
+			WHERE c.id_produto IS NULL
+		) AS p`
+	return util.CountRowsWithFilter(s.db, ctx, query, filter, "p")
+}
+
 func (s *Store) CreateComercial(ctx context.Context, props *model.Comercial) error {
+	return s.db.RecordWrite(s.createComercial(ctx, props))
+}
+
+func (s *Store) createComercial(ctx context.Context, props *model.Comercial) error {
 	// Inicia a transação
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -129,6 +214,10 @@ func (s *Store) CreateComercial(ctx context.Context, props *model.Comercial) err
 }
 
 func (s *Store) Create(ctx context.Context, props *model.Produto) error {
+	return s.db.RecordWrite(s.create(ctx, props))
+}
+
+func (s *Store) create(ctx context.Context, props *model.Produto) error {
 	query := "INSERT INTO Produto (nome, categoria, marca) VALUES ($1, $2, $3) RETURNING id_produto;"
 
 	row := s.db.QueryRowContext(ctx, query, props.Nome, props.Categoria, props.Marca)
@@ -139,6 +228,10 @@ func (s *Store) Create(ctx context.Context, props *model.Produto) error {
 	return nil
 }
 func (s *Store) UpdateComercial(ctx context.Context, props *model.Comercial) error {
+	return s.db.RecordWrite(s.updateComercial(ctx, props))
+}
+
+func (s *Store) updateComercial(ctx context.Context, props *model.Comercial) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -170,6 +263,10 @@ func (s *Store) UpdateComercial(ctx context.Context, props *model.Comercial) err
 }
 
 func (s *Store) Update(ctx context.Context, props *model.Produto) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.Produto) error {
 	query := "UPDATE Produto SET nome = $1, categoria = $2, marca = $3 WHERE id_produto = $4;"
 
 	res, err := s.db.ExecContext(ctx, query, props.Nome, props.Categoria, props.Marca, props.Id)
@@ -187,6 +284,10 @@ func (s *Store) Update(ctx context.Context, props *model.Produto) error {
 }
 
 func (s *Store) GetComercialByID(ctx context.Context, id int64) (*model.Comercial, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.Comercial, error) { return s.getComercialByID(ctx, id) })
+}
+
+func (s *Store) getComercialByID(ctx context.Context, id int64) (*model.Comercial, error) {
 	query := `
 		SELECT p.id_produto, p.nome, p.categoria, p.marca, c.preco_venda
 		FROM Produto p
@@ -212,6 +313,10 @@ func (s *Store) GetComercialByID(ctx context.Context, id int64) (*model.Comercia
 }
 
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.Produto, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.Produto, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.Produto, error) {
 	query := "SELECT id_produto, nome, categoria, marca FROM Produto WHERE id_produto = $1"
 	row := s.db.QueryRowContext(ctx, query, id)
 	c := model.Produto{}
@@ -223,6 +328,10 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.Produto, error) {
 }
 
 func (s *Store) GetQntByID(ctx context.Context, id int64) (*model.ProdutoWithQnt, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.ProdutoWithQnt, error) { return s.getQntByID(ctx, id) })
+}
+
+func (s *Store) getQntByID(ctx context.Context, id int64) (*model.ProdutoWithQnt, error) {
 
 	// Quantidade de produtos disponiveis
 	// Resultado = inicias - estrados - vendidos
@@ -247,7 +356,48 @@ func (s *Store) GetQntByID(ctx context.Context, id int64) (*model.ProdutoWithQnt
 	return &model, nil
 }
 
+const deletePreviewQuery = `SELECT
+		(SELECT COUNT(*) FROM ProdutoComercial WHERE id_produto = p.id_produto),
+		(SELECT COUNT(*) FROM Lote WHERE id_produto = p.id_produto),
+		(SELECT COUNT(*) FROM item_venda iv JOIN Lote l ON l.id_lote = iv.id_lote WHERE l.id_produto = p.id_produto),
+		(SELECT COUNT(*) FROM contem_item_oferta WHERE id_produto = p.id_produto)
+	FROM Produto p WHERE p.id_produto = $1;`
+
+// Conta o que seria removido em cascata pelo Delete, sem remover nada. Itens de venda
+// dos lotes do produto e itens de oferta que o contêm são reportados em Bloqueios,
+// pois fazem o Delete falhar.
+func (s *Store) DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.DeletePreview, error) { return s.deletePreview(ctx, id) })
+}
+
+func (s *Store) deletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	counts, err := util.CountCascade(s.db, ctx, deletePreviewQuery, id, "produtocomercial", "lote", "item_venda", "contem_item_oferta")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, types.ErrNotFound
+		}
+		return nil, err
+	}
+	preview := &model.DeletePreview{Entidade: "produto", Id: id, Cascata: map[string]int64{
+		"produtocomercial": counts["produtocomercial"],
+		"lote":             counts["lote"],
+	}}
+	for _, table := range []string{"item_venda", "contem_item_oferta"} {
+		if n := counts[table]; n > 0 {
+			if preview.Bloqueios == nil {
+				preview.Bloqueios = make(map[string]int64)
+			}
+			preview.Bloqueios[table] = n
+		}
+	}
+	return preview, nil
+}
+
 func (s *Store) Delete(ctx context.Context, id int64) error {
+	return s.db.RecordWrite(s.delete(ctx, id))
+}
+
+func (s *Store) delete(ctx context.Context, id int64) error {
 	// Derivadas do produto serão apagadas automaticamente por conta da herança
 	query := "DELETE FROM Produto WHERE id_produto = $1"
 	_, err := s.db.ExecContext(ctx, query, id)
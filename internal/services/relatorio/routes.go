@@ -22,7 +22,7 @@ func NewHandler(store RelatorioStore) *Handler {
 	return &Handler{store: store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /relatorios/financeiro", h.getFinancialReport)
 	mux.HandleFunc("GET /relatorios/folha-pagamento", h.getPayrollReport)
 }
@@ -69,8 +69,8 @@ func (h *Handler) getFinancialReport(w http.ResponseWriter, r *http.Request) {
 	// Call store to build the report
 	report, err := h.store.GetFinancialReport(ctx, start, end, granularity, projection)
 	if err != nil {
-		// Return internal server error with the error message
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		// Return the error with the status of its kind (500 when unclassified)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -108,10 +108,17 @@ func (h *Handler) getPayrollReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if tipoFuncionario != "" {
+		if _, err := model.ParseTipoFuncionario(tipoFuncionario); err != nil {
+			util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Chamar store para gerar o relatório
 	report, err := h.store.GetPayrollReport(ctx, start, end, tipoFuncionario)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 
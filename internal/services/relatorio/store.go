@@ -9,14 +9,17 @@ import (
 	"time"
 
 	"edna/internal/model"
+	"edna/internal/util"
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
+	// Fuso da aplicação, em que as datas do período e da série são interpretadas
+	loc *time.Location
 }
 
-func NewStore(db *sql.DB) *Store {
-	return &Store{db: db}
+func NewStore(db *util.DB, loc *time.Location) *Store {
+	return &Store{db: db, loc: loc}
 }
 
 // GetPayrollReport gera um relatório de folha de pagamento mensal para o período especificado
@@ -24,6 +27,10 @@ func NewStore(db *sql.DB) *Store {
 // - tipoFuncionario: filtro opcional por tipo de funcionário (garcom, seguranca, caixa, faxineiro, balconista)
 // - retorna folhas de pagamento mensais para cada mês dentro do período
 func (s *Store) GetPayrollReport(ctx context.Context, start, end, tipoFuncionario string) (model.RelatorioFolhaPagamento, error) {
+	return util.RetryRead(ctx, s.db, func() (model.RelatorioFolhaPagamento, error) { return s.getPayrollReport(ctx, start, end, tipoFuncionario) })
+}
+
+func (s *Store) getPayrollReport(ctx context.Context, start, end, tipoFuncionario string) (model.RelatorioFolhaPagamento, error) {
 	var report model.RelatorioFolhaPagamento
 
 	// Validação básica
@@ -32,11 +39,11 @@ func (s *Store) GetPayrollReport(ctx context.Context, start, end, tipoFuncionari
 	}
 
 	// Parse das datas
-	startT, err := time.Parse("2006-01-02", start)
+	startT, err := util.ParseDate(start, s.loc)
 	if err != nil {
 		return report, fmt.Errorf("data de início inválida: %w", err)
 	}
-	endT, err := time.Parse("2006-01-02", end)
+	endT, err := util.ParseDate(end, s.loc)
 	if err != nil {
 		return report, fmt.Errorf("data de fim inválida: %w", err)
 	}
@@ -119,6 +126,9 @@ func (s *Store) generateMonthlyPayroll(ctx context.Context, month time.Time, tip
 	var totalSalarioBase, totalBonificacoes float64
 
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return folha, err
+		}
 		var funcio model.FuncionarioFolhaPagamento
 		err := rows.Scan(
 			&funcio.IdFuncionario,
@@ -160,13 +170,13 @@ func (s *Store) generateMonthlyPayroll(ctx context.Context, month time.Time, tip
 
 // calculateBonificacao calcula bonificação baseada no tipo de funcionário
 // Regras simples: garcom e balconista recebem 10%, segurança 15%, outros 5%
-func (s *Store) calculateBonificacao(tipo string, salarioBase float64) float64 {
-	switch strings.ToLower(tipo) {
-	case "garcom", "balconista":
+func (s *Store) calculateBonificacao(tipo model.TipoFuncionario, salarioBase float64) float64 {
+	switch model.TipoFuncionario(strings.ToLower(tipo.String())) {
+	case model.TipoGarcom, model.TipoBalconista:
 		return salarioBase * 0.10
-	case "seguranca":
+	case model.TipoSeguranca:
 		return salarioBase * 0.15
-	case "caixa", "faxineiro":
+	case model.TipoCaixa, model.TipoFaxineiro:
 		return salarioBase * 0.05
 	default:
 		return 0.0
@@ -178,6 +188,10 @@ func (s *Store) calculateBonificacao(tipo string, salarioBase float64) float64 {
 // - granularity: "day", "week", "month"
 // - projectionPeriods: number of future periods to project (0 to disable)
 func (s *Store) GetFinancialReport(ctx context.Context, start, end, granularity string, projectionPeriods int) (model.RelatorioFinanceiro, error) {
+	return util.RetryRead(ctx, s.db, func() (model.RelatorioFinanceiro, error) { return s.getFinancialReport(ctx, start, end, granularity, projectionPeriods) })
+}
+
+func (s *Store) getFinancialReport(ctx context.Context, start, end, granularity string, projectionPeriods int) (model.RelatorioFinanceiro, error) {
 	var report model.RelatorioFinanceiro
 
 	// Basic validation
@@ -192,11 +206,11 @@ func (s *Store) GetFinancialReport(ctx context.Context, start, end, granularity
 	}
 
 	// Parse dates
-	startT, err := time.Parse("2006-01-02", start)
+	startT, err := util.ParseDate(start, s.loc)
 	if err != nil {
 		return report, fmt.Errorf("invalid start date: %w", err)
 	}
-	endT, err := time.Parse("2006-01-02", end)
+	endT, err := util.ParseDate(end, s.loc)
 	if err != nil {
 		return report, fmt.Errorf("invalid end date: %w", err)
 	}
@@ -283,6 +297,9 @@ func (s *Store) fetchReceita(ctx context.Context, start, end, granularity string
 	defer rows.Close()
 
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var period time.Time
 		var receita sql.NullFloat64
 		if err := rows.Scan(&period, &receita); err != nil {
@@ -292,7 +309,7 @@ func (s *Store) fetchReceita(ctx context.Context, start, end, granularity string
 		if receita.Valid {
 			val = receita.Float64
 		}
-		period = truncateToGranularity(period, granularity)
+		period = truncateToGranularity(wallClock(period, s.loc), granularity)
 		agg[period] = val
 	}
 	if err := rows.Err(); err != nil {
@@ -324,6 +341,9 @@ func (s *Store) fetchDespesa(ctx context.Context, start, end, granularity string
 	defer rows.Close()
 
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var period time.Time
 		var despesa sql.NullFloat64
 		if err := rows.Scan(&period, &despesa); err != nil {
@@ -333,7 +353,7 @@ func (s *Store) fetchDespesa(ctx context.Context, start, end, granularity string
 		if despesa.Valid {
 			val = despesa.Float64
 		}
-		period = truncateToGranularity(period, granularity)
+		period = truncateToGranularity(wallClock(period, s.loc), granularity)
 		agg[period] = val
 	}
 	if err := rows.Err(); err != nil {
@@ -394,6 +414,12 @@ func sqlDateTruncArg(granularity string) string {
 	}
 }
 
+// wallClock reinterpreta um valor `timestamp`/`date` do banco, lido como UTC, com o mesmo
+// horário no fuso da aplicação `loc`, para que as chaves casem com as datas da série.
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
 // truncateToGranularity truncates a time.Time to the requested granularity.
 func truncateToGranularity(t time.Time, granularity string) time.Time {
 	switch granularity {
@@ -3,8 +3,8 @@ package venda
 import (
 	"context"
 	"edna/internal/model"
+	"edna/internal/types"
 	"edna/internal/util"
-	"encoding/json"
 	"net/http"
 )
 
@@ -14,18 +14,21 @@ type Handler struct {
 
 type VendaStore interface {
 	GetAll(ctx context.Context, filter util.Filter) ([]model.Venda, error)
+	Count(ctx context.Context, filter util.Filter) (int64, error)
 	Create(ctx context.Context, props *model.Venda) error
 	GetByID(ctx context.Context, id int64) (*model.Venda, error)
 	Update(ctx context.Context, props *model.Venda) error
 	Delete(ctx context.Context, id int64) (*model.Venda, error)
+	DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error)
 }
 
 func NewHandler(store VendaStore) *Handler {
 	return &Handler{store}
 }
 
-func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+func (h *Handler) RegisterRoutes(mux util.Mux) {
 	mux.HandleFunc("GET /vendas", h.getAll)
+	mux.HandleFunc("GET /vendas/count", h.count)
 	mux.HandleFunc("POST /vendas", h.create)
 	mux.HandleFunc("GET /vendas/{id}", h.fetch)
 	mux.HandleFunc("PUT /vendas/{id}", h.update)
@@ -56,15 +59,49 @@ func (h *Handler) getAll(w http.ResponseWriter, r *http.Request) {
 	}
 	vendas, err := h.store.GetAll(ctx, filters)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	util.SetPaginationHeaders(w, r, filters, len(vendas), total)
+
 	err = util.WriteJSON(w, http.StatusOK, vendas)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// @Summary Count Vendas
+// @Description Returns how many rows match the filters accepted by GET /vendas, ignoring sort and pagination.
+// @Tags Venda
+// @Produce json
+// @Success 200 {object} types.CountResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /vendas/count [get]
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), util.RequestTimeout)
+	defer cancel()
+
+	filters, err := NewVendaFilter(r.URL.Query())
+	if err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, err := h.store.Count(ctx, filters)
+	if err != nil {
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, types.CountResponse{Count: total})
+}
+
 // @Summary Create Venda
 // @Tags Venda
 // @Accept json
@@ -84,16 +121,20 @@ func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.VendaCreate
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToVenda()
 	err = h.store.Create(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -121,7 +162,7 @@ func (h *Handler) fetch(w http.ResponseWriter, r *http.Request) {
 
 	venda, err := h.store.GetByID(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusInternalServerError)
+		util.StoreErrorJSON(w, err, http.StatusInternalServerError)
 		return
 	}
 	if venda == nil {
@@ -156,17 +197,21 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var payload model.VendaCreate
-	err = json.NewDecoder(r.Body).Decode(&payload)
+	err = util.ReadJSON(r, &payload)
 	if err != nil {
 		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := util.Validate(payload); err != nil {
+		util.ErrorJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	model := payload.ToVenda()
 	model.Id = id
 	err = h.store.Update(ctx, &model)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
@@ -177,6 +222,7 @@ func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
 // @Tags Venda
 // @Produce json
 // @Param id path int true "Venda ID"
+// @Param dry_run query bool false "Only report what would be deleted in cascade, returning a model.DeletePreview"
 // @Success 200 {object} model.Venda
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 422 {object} types.ErrorResponse
@@ -191,9 +237,23 @@ func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if util.IsDryRun(r) {
+		preview, err := h.store.DeletePreview(ctx, id)
+		if err != nil {
+			if err == types.ErrNotFound {
+				util.ErrorJSON(w, "Venda not found.", http.StatusNotFound)
+				return
+			}
+			util.StoreErrorJSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		util.WriteJSON(w, http.StatusOK, preview)
+		return
+	}
+
 	model, err := h.store.Delete(ctx, id)
 	if err != nil {
-		util.ErrorJSON(w, err.Error(), http.StatusUnprocessableEntity)
+		util.StoreErrorJSON(w, err, http.StatusUnprocessableEntity)
 		return
 	}
 
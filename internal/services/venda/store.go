@@ -9,39 +9,64 @@ import (
 )
 
 type Store struct {
-	db *sql.DB
+	db *util.DB
 }
 
-func NewStore(db *sql.DB) *Store {
+func NewStore(db *util.DB) *Store {
 	return &Store{db}
 }
 
 func (s *Store) GetAll(ctx context.Context, filter util.Filter) ([]model.Venda, error) {
+	return util.RetryRead(ctx, s.db, func() ([]model.Venda, error) { return s.getAll(ctx, filter) })
+}
+
+func (s *Store) getAll(ctx context.Context, filter util.Filter) ([]model.Venda, error) {
 
 	query := "SELECT id_venda, id_cliente, id_funcionario, data_hora_venda, data_hora_pagamento, tipo_pagamento FROM Venda AS v"
 	rows, err := util.QueryRowsWithFilter(s.db, ctx, query, &filter, "v")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	vendas := make([]model.Venda, 0)
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var venda model.Venda
 		if err := rows.Scan(&venda.Id, &venda.IdCliente, &venda.IdFuncionario, &venda.DataHoraVenda, &venda.DataHoraPagamento, &venda.TipoPagamento); err != nil {
 			return nil, err
 		}
 		vendas = append(vendas, venda)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return vendas, nil
 }
 
+// Conta quantos registros satisfazem o filtro, ignorando a paginação
+func (s *Store) Count(ctx context.Context, filter util.Filter) (int64, error) {
+	query := "SELECT COUNT(*) FROM Venda AS v"
+	return util.CountRowsWithFilter(s.db, ctx, query, &filter, "v")
+}
+
 func (s *Store) Create(ctx context.Context, venda *model.Venda) error {
+	return s.db.RecordWrite(s.create(ctx, venda))
+}
+
+func (s *Store) create(ctx context.Context, venda *model.Venda) error {
 	query := "INSERT INTO Venda (id_cliente, id_funcionario, data_hora_venda, data_hora_pagamento, tipo_pagamento) VALUES ($1, $2, $3, $4, $5) RETURNING id_venda"
 	res := s.db.QueryRowContext(ctx, query, venda.IdCliente, venda.IdFuncionario, venda.DataHoraVenda, venda.DataHoraPagamento, venda.TipoPagamento)
 	return res.Scan(&venda.Id)
 }
 
 func (s *Store) GetByID(ctx context.Context, id int64) (*model.Venda, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.Venda, error) { return s.getByID(ctx, id) })
+}
+
+func (s *Store) getByID(ctx context.Context, id int64) (*model.Venda, error) {
 	query := "SELECT id_venda, id_cliente, id_funcionario, data_hora_venda, data_hora_pagamento, tipo_pagamento FROM Venda WHERE id_venda = $1"
 	row := s.db.QueryRowContext(ctx, query, id)
 	var venda model.Venda
@@ -56,6 +81,10 @@ func (s *Store) GetByID(ctx context.Context, id int64) (*model.Venda, error) {
 }
 
 func (s *Store) Update(ctx context.Context, props *model.Venda) error {
+	return s.db.RecordWrite(s.update(ctx, props))
+}
+
+func (s *Store) update(ctx context.Context, props *model.Venda) error {
 	query := "UPDATE Venda SET id_cliente = $1, id_funcionario = $2, data_hora_venda = $3, data_hora_pagamento = $4, tipo_pagamento = $5 WHERE id_venda = $6;"
 	res, err := s.db.ExecContext(ctx, query, props.IdCliente, props.IdFuncionario, props.DataHoraVenda, props.DataHoraPagamento, props.TipoPagamento, props.Id)
 	if err != nil {
@@ -72,6 +101,11 @@ func (s *Store) Update(ctx context.Context, props *model.Venda) error {
 }
 
 func (s *Store) Delete(ctx context.Context, id int64) (*model.Venda, error) {
+	result, err := s.delete(ctx, id)
+	return result, s.db.RecordWrite(err)
+}
+
+func (s *Store) delete(ctx context.Context, id int64) (*model.Venda, error) {
 	query := "DELETE FROM Venda WHERE id_venda = $1 RETURNING id_venda, id_cliente, id_funcionario, data_hora_venda, data_hora_pagamento, tipo_pagamento;"
 
 	var venda model.Venda
@@ -85,3 +119,25 @@ func (s *Store) Delete(ctx context.Context, id int64) (*model.Venda, error) {
 	}
 	return &venda, nil
 }
+
+// Linhas removidas em cascata junto com a venda, na ordem das tabelas passadas a CountCascade
+const deletePreviewQuery = `SELECT
+		(SELECT COUNT(*) FROM item_venda WHERE id_venda = v.id_venda),
+		(SELECT COUNT(*) FROM aplica_oferta WHERE id_venda = v.id_venda)
+	FROM Venda v WHERE v.id_venda = $1;`
+
+// Conta o que seria removido em cascata pelo Delete, sem remover nada.
+func (s *Store) DeletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	return util.RetryRead(ctx, s.db, func() (*model.DeletePreview, error) { return s.deletePreview(ctx, id) })
+}
+
+func (s *Store) deletePreview(ctx context.Context, id int64) (*model.DeletePreview, error) {
+	cascata, err := util.CountCascade(s.db, ctx, deletePreviewQuery, id, "item_venda", "aplica_oferta")
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, types.ErrNotFound
+		}
+		return nil, err
+	}
+	return &model.DeletePreview{Entidade: "venda", Id: id, Cascata: cascata}, nil
+}
@@ -0,0 +1,6 @@
+package types
+
+// Resposta dos endpoints `GET /<entidade>/count`
+type CountResponse struct {
+	Count int64 `json:"count" xml:"count"`
+}
@@ -1,16 +1,49 @@
 package types
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
 
 var (
 	ErrNotFound = errors.New("Not found")
 	ErrInternalServer = errors.New("Internal error")
+
+	// Violação de unicidade, o registro já existe
+	ErrAlreadyExists = errors.New("Already exists")
+	// O registro referenciado (chave estrangeira) não existe
+	ErrReferenceNotFound = errors.New("Referenced record not found")
+	// O registro ainda é referenciado por outros e não pode ser removido
+	ErrStillReferenced = errors.New("Record is still referenced")
+	// O circuit breaker do banco está aberto, a consulta nem foi feita
+	ErrDatabaseUnavailable = errors.New("Database unavailable")
 )
 
+// Corpo de todas as respostas de erro da API
 type ErrorResponse struct {
-	Message string `json:"detail"`
+	// Código do erro derivado do status HTTP, ex: "not_found"
+	Error   string `json:"error" xml:"error"`
+	Message string `json:"detail" xml:"detail"`
+	// Momento do erro em RFC 3339 (UTC)
+	Timestamp string `json:"timestamp" xml:"timestamp"`
+}
+
+func NewErrorResponse(status int, msg string) ErrorResponse {
+	return ErrorResponse{
+		Error:     ErrorCode(status),
+		Message:   msg,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
 }
 
-func NewErrorResponse(msg string) ErrorResponse {
-	return ErrorResponse{Message: msg}
+// Código textual de um status HTTP de erro: 404 vira "not_found", 503 "service_unavailable"
+func ErrorCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "unknown_error"
+	}
+	text = strings.ReplaceAll(strings.ToLower(text), "-", "_")
+	return strings.ReplaceAll(text, " ", "_")
 }
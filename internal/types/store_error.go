@@ -0,0 +1,37 @@
+package types
+
+// Categoria de uma falha de acesso ao banco, independente do driver
+type ErrorKind int
+
+const (
+	KindUnknown ErrorKind = iota
+	KindNotFound
+	KindConflict
+	KindConnection
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindConnection:
+		return "connection"
+	}
+	return "unknown"
+}
+
+// Erro retornado pela camada de acesso ao banco, com o erro original do driver em Err
+type StoreError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *StoreError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StoreError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"edna/internal/types"
+)
+
+// Estados do circuit breaker do banco
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// Tempo sugerido aos clientes recusados enquanto a requisição de teste do circuito meio
+// aberto não termina. O cooldown já passou, então não há um tempo restante a informar.
+const probeRetryAfter = time.Second
+
+// Abre após `threshold` falhas de conexão seguidas e recusa as consultas durante `cooldown`,
+// em vez de deixar as requisições se acumularem esperando o timeout. Passado o cooldown fica
+// meio aberto: uma única requisição de teste é liberada e o seu resultado decide se o circuito
+// fecha (sucesso) ou abre de novo (falha). Todos os métodos aceitam um breaker nil.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	open      bool
+	openedAt  time.Time
+	// Há uma requisição de teste em andamento no circuito meio aberto
+	probing bool
+	// Contador das requisições de teste, para que done só libere a vez da própria requisição
+	probes uint64
+	now    func() time.Time
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Estado atual: closed, open ou half_open. Vazio para um breaker nil.
+func (cb *CircuitBreaker) State() string {
+	if cb == nil {
+		return ""
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state()
+}
+
+func (cb *CircuitBreaker) state() string {
+	switch {
+	case !cb.open:
+		return BreakerClosed
+	case cb.now().Sub(cb.openedAt) >= cb.cooldown:
+		return BreakerHalfOpen
+	default:
+		return BreakerOpen
+	}
+}
+
+// Indica se uma requisição pode usar o banco. Com o circuito aberto retorna false e quanto
+// falta para o cooldown terminar. Meio aberto, libera só a primeira requisição (o teste) e
+// recusa as demais até que Record registre o resultado dela. A requisição liberada deve
+// chamar done ao terminar: se ela não chegou a registrar um resultado (ex: foi recusada na
+// validação, antes de consultar o banco), a vez de teste passa para a próxima requisição.
+func (cb *CircuitBreaker) Allow() (ok bool, retryAfter time.Duration, done func()) {
+	if cb == nil {
+		return true, 0, func() {}
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state() {
+	case BreakerOpen:
+		return false, cb.cooldown - cb.now().Sub(cb.openedAt), func() {}
+	case BreakerHalfOpen:
+		if cb.probing {
+			return false, probeRetryAfter, func() {}
+		}
+		cb.probing = true
+		cb.probes++
+		probe := cb.probes
+		return true, 0, func() {
+			cb.mu.Lock()
+			defer cb.mu.Unlock()
+			if cb.probing && cb.probes == probe {
+				cb.probing = false
+			}
+		}
+	}
+	return true, 0, func() {}
+}
+
+// Registra o resultado de uma consulta ou escrita. Apenas falhas de conexão e timeouts contam
+// como falha; qualquer outro resultado mostra que o banco respondeu e fecha o circuito.
+// Requisições canceladas pelo cliente não dizem nada sobre o banco e são ignoradas.
+func (cb *CircuitBreaker) Record(err error) {
+	if cb == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !isDatabaseFailure(err) {
+		cb.open, cb.failures, cb.probing = false, 0, false
+		return
+	}
+	cb.failures++
+	if cb.state() == BreakerHalfOpen || cb.failures >= cb.threshold {
+		cb.open, cb.openedAt, cb.failures, cb.probing = true, cb.now(), 0, false
+	}
+}
+
+func isDatabaseFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, context.DeadlineExceeded) || storeErrorKind(err) == types.KindConnection
+}
@@ -0,0 +1,107 @@
+package util
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"edna/internal/types"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	cb := NewCircuitBreaker(3, 30*time.Second)
+	cb.now = func() time.Time { return now }
+
+	// Erros de consulta mostram que o banco respondeu e não contam como falha
+	cb.Record(&pgconn.PgError{Code: "23505"})
+	cb.Record(driver.ErrBadConn)
+	cb.Record(&pgconn.PgError{Code: "08006"})
+	if state := cb.State(); state != BreakerClosed {
+		t.Fatalf("expected closed after 2 failures; got %s", state)
+	}
+	cb.Record(context.DeadlineExceeded)
+	if ok, retryAfter, _ := cb.Allow(); ok || retryAfter != 30*time.Second {
+		t.Fatalf("expected open for 30s after 3 failures; got allowed=%t retry after %s", ok, retryAfter)
+	}
+
+	// Passado o cooldown, uma nova falha abre o circuito de novo
+	now = now.Add(30 * time.Second)
+	if ok, _, _ := cb.Allow(); !ok || cb.State() != BreakerHalfOpen {
+		t.Fatalf("expected half open after the cooldown; got %s", cb.State())
+	}
+	cb.Record(driver.ErrBadConn)
+	if state := cb.State(); state != BreakerOpen {
+		t.Fatalf("expected a failure while half open to reopen; got %s", state)
+	}
+
+	// E um sucesso o fecha
+	now = now.Add(30 * time.Second)
+	cb.Record(nil)
+	if state := cb.State(); state != BreakerClosed {
+		t.Errorf("expected closed after a success; got %s", state)
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	cb := NewCircuitBreaker(1, 30*time.Second)
+	cb.now = func() time.Time { return now }
+	cb.Record(driver.ErrBadConn)
+	now = now.Add(30 * time.Second)
+
+	ok, _, done := cb.Allow()
+	if !ok {
+		t.Fatal("expected the first request after the cooldown to be the probe")
+	}
+	if ok, retryAfter, _ := cb.Allow(); ok || retryAfter != probeRetryAfter {
+		t.Fatalf("expected other requests to wait for the probe; got allowed=%t retry after %s", ok, retryAfter)
+	}
+
+	// Um probe que termina sem consultar o banco libera a vez para outra requisição
+	done()
+	ok, _, done = cb.Allow()
+	if !ok {
+		t.Fatal("expected a new probe after the previous one finished without a result")
+	}
+
+	// Cancelamentos não decidem o teste
+	cb.Record(context.Canceled)
+	if ok, _, _ := cb.Allow(); ok {
+		t.Fatal("expected a cancelled query not to release the probe")
+	}
+
+	cb.Record(nil)
+	done()
+	if ok, _, _ := cb.Allow(); !ok || cb.State() != BreakerClosed {
+		t.Errorf("expected closed after the probe succeeded; got %s", cb.State())
+	}
+}
+
+func TestRecordWrite(t *testing.T) {
+	db := &DB{Breaker: NewCircuitBreaker(1, time.Minute)}
+	if err := db.RecordWrite(driver.ErrBadConn); !errors.Is(err, driver.ErrBadConn) {
+		t.Fatalf("expected the write error back; got %v", err)
+	}
+	if state := db.Breaker.State(); state != BreakerOpen {
+		t.Errorf("expected a failed write to open the breaker; got %s", state)
+	}
+}
+
+func TestRetryReadShortCircuits(t *testing.T) {
+	db := &DB{Breaker: NewCircuitBreaker(1, time.Minute)}
+	db.Breaker.Record(driver.ErrBadConn)
+
+	calls := 0
+	_, err := RetryRead(context.Background(), db, func() (int64, error) {
+		calls++
+		return 0, nil
+	})
+	if !errors.Is(err, types.ErrDatabaseUnavailable) || calls != 0 {
+		t.Errorf("expected ErrDatabaseUnavailable without querying; got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,17 @@
+package util
+
+import "time"
+
+// Formato das datas sem horário aceitas na API
+const DateLayout = "2006-01-02"
+
+// Interpreta uma data YYYY-MM-DD como meia-noite no fuso `loc` (APP_TIMEZONE)
+func ParseDate(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, value, loc)
+}
+
+// Retorna a data de `now` no fuso `loc` (APP_TIMEZONE), à meia-noite
+func Today(now time.Time, loc *time.Location) time.Time {
+	y, m, d := now.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
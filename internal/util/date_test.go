@@ -0,0 +1,30 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDatesUseGivenTimezone(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// 02:00 UTC ainda é o dia anterior em São Paulo (UTC-3)
+	now := time.Date(2024, time.March, 10, 2, 0, 0, 0, time.UTC)
+	today := Today(now, saoPaulo)
+	if today.Format(DateLayout) != "2024-03-09" {
+		t.Errorf("expected today to be 2024-03-09; got %s", today.Format(DateLayout))
+	}
+
+	parsed, err := ParseDate("2024-03-10", saoPaulo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2024, time.March, 10, 3, 0, 0, 0, time.UTC); !parsed.Equal(want) {
+		t.Errorf("expected 2024-03-10 to start at %s; got %s", want, parsed.UTC())
+	}
+	if !today.Before(parsed) {
+		t.Errorf("expected %s to be before %s", today, parsed)
+	}
+}
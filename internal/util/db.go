@@ -0,0 +1,26 @@
+package util
+
+import "database/sql"
+
+// Conexão usada pelos stores junto com as políticas aplicadas às suas consultas. Cada
+// servidor monta a sua a partir da Config, então dois servidores com configurações
+// diferentes não interferem um no outro. O valor zero de cada campo desabilita a política.
+type DB struct {
+	*sql.DB
+	// Máximo de linhas retornadas por uma listagem sem `limit` (0 desabilita). Evita
+	// carregar tabelas inteiras em memória; o resultado é truncado e sinalizado com o
+	// header `Warning` por SetPaginationHeaders.
+	MaxUnboundedRows uint32
+	// Novas tentativas de uma consulta de leitura abortada por um erro transitório (0 desabilita)
+	ReadRetries int
+	// Circuit breaker das consultas ao banco (nil desabilita)
+	Breaker *CircuitBreaker
+}
+
+// Registra em db.Breaker o resultado de uma escrita e o devolve. Escritas não passam por
+// RetryRead, pois não podem ser repetidas, mas uma falha de conexão nelas mostra tanto quanto
+// uma leitura que o banco está fora do ar.
+func (db *DB) RecordWrite(err error) error {
+	db.Breaker.Record(err)
+	return err
+}
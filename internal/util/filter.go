@@ -155,6 +155,7 @@ func (ff *Filter) GetFilterTime(params url.Values, key string) error {
 			return errors.New(fmt.Sprintf("Invalid operator for query `%s`", filterKey))
 		}
 
+		// O fuso não importa: colunas `date` e `timestamp` recebem apenas o horário de parede
 		v, err := time.Parse("2006-01-02 15:04:05", parts[1])
 		if err != nil {
 			return err
@@ -167,9 +168,9 @@ func (ff *Filter) GetFilterTime(params url.Values, key string) error {
 	return nil
 }
 
-// Cria uma sql query apartir de Filter e adiciona valores para preencher a query em values
-func (ff *Filter) ToQuery(values *[]any, tableAlias string) string {
-	// condições
+// Cria apenas as condições (WHERE) da query apartir de Filter, sem ordenação nem paginação.
+// Retorna false caso algum operador seja inválido.
+func (ff *Filter) ToWhereQuery(values *[]any, tableAlias string) (string, bool) {
 	var query string
 	i := 0
 	for k, v := range ff.Filters {
@@ -204,10 +205,20 @@ func (ff *Filter) ToQuery(values *[]any, tableAlias string) string {
 			*values = append(*values, v.Value)
 			query += fmt.Sprintf(" %s.%s ILIKE '%%' || $%d || '%%'", tableAlias, k, len(*values))
 		default:
-			return ""
+			return "", false
 		}
 		i += 1
 	}
+	return query, true
+}
+
+// Cria uma sql query apartir de Filter e adiciona valores para preencher a query em values
+func (ff *Filter) ToQuery(values *[]any, tableAlias string) string {
+	// condições
+	query, ok := ff.ToWhereQuery(values, tableAlias)
+	if !ok {
+		return ""
+	}
 
 	// ordenação
 	for i, v := range ff.Sorts {
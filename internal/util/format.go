@@ -0,0 +1,170 @@
+package util
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Formatos de resposta aceitos na negociação de conteúdo (header `Accept`)
+const (
+	FormatJSON = "application/json"
+	FormatXML  = "application/xml"
+)
+
+// ResponseWriter cujas respostas escritas por WriteJSON são indentadas
+type prettyJSONWriter struct {
+	http.ResponseWriter
+}
+
+func (w *prettyJSONWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// / Envolve w para que WriteJSON escreva o JSON indentado, útil para depuração (`?pretty=true`)
+func WithPrettyJSON(w http.ResponseWriter) http.ResponseWriter {
+	return &prettyJSONWriter{ResponseWriter: w}
+}
+
+// ResponseWriter cujas respostas escritas por WriteJSON são codificadas em XML
+type xmlWriter struct {
+	http.ResponseWriter
+}
+
+func (w *xmlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// / Envolve w para que WriteJSON escreva o corpo em XML (`Accept: application/xml`)
+func WithXML(w http.ResponseWriter) http.ResponseWriter {
+	return &xmlWriter{ResponseWriter: w}
+}
+
+// Raiz dos documentos XML. Listas viram uma sequência de elementos <item>.
+type xmlEnvelope struct {
+	XMLName xml.Name `xml:"response"`
+	Value   any      `xml:"item"`
+}
+
+// / Escolhe o formato da resposta a partir do header `Accept`, respeitando os pesos `q`.
+// / Sem header, ou aceitando qualquer tipo, o formato é JSON. Retorna false quando
+// / nenhum dos tipos aceitos pelo cliente é suportado.
+func NegotiateFormat(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return FormatJSON, true
+	}
+
+	format, best := "", 0.0
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		var candidate string
+		switch mediaType {
+		case "application/json", "application/*", "*/*":
+			candidate = FormatJSON
+		case "application/xml", "text/xml":
+			candidate = FormatXML
+		default:
+			continue
+		}
+		if q > best {
+			format, best = candidate, q
+		}
+	}
+	return format, format != ""
+}
+
+// Handler que declara os tipos de mídia que produz além de JSON e XML
+type producesHandler struct {
+	http.Handler
+	mediaTypes []string
+}
+
+func (h producesHandler) MediaTypes() []string {
+	return h.mediaTypes
+}
+
+// / Declara que `next` também responde nos tipos `mediaTypes` (ex: application/x-ndjson),
+// / para que a negociação de conteúdo aceite requisições que pedem esses tipos.
+func Produces(next http.HandlerFunc, mediaTypes ...string) http.Handler {
+	return producesHandler{Handler: next, mediaTypes: mediaTypes}
+}
+
+// Tipos de mídia declarados com Produces, nil para handlers comuns
+func MediaTypes(h http.Handler) []string {
+	if p, ok := h.(interface{ MediaTypes() []string }); ok {
+		return p.MediaTypes()
+	}
+	return nil
+}
+
+// Indica se o header `Accept` aceita algum dos `mediaTypes` (com peso q maior que zero)
+func AcceptsMediaType(accept string, mediaTypes []string) bool {
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			continue
+		}
+		for _, candidate := range mediaTypes {
+			if mediaType == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Codifica v no formato escolhido para w, retornando também o Content-Type
+func encodeBody(w http.ResponseWriter, v any) ([]byte, string, error) {
+	pretty := wraps[*prettyJSONWriter](w)
+	if wraps[*xmlWriter](w) {
+		envelope := xmlEnvelope{Value: v}
+		var res []byte
+		var err error
+		if pretty {
+			res, err = xml.MarshalIndent(envelope, "", "  ")
+		} else {
+			res, err = xml.Marshal(envelope)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return append([]byte(xml.Header), res...), FormatXML, nil
+	}
+
+	if pretty {
+		res, err := json.MarshalIndent(v, "", "  ")
+		return res, FormatJSON, err
+	}
+	res, err := json.Marshal(v)
+	return res, FormatJSON, err
+}
+
+// Procura um ResponseWriter do tipo T entre os que envolvem w
+func wraps[T http.ResponseWriter](w http.ResponseWriter) bool {
+	for {
+		if _, ok := w.(T); ok {
+			return true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+}
@@ -3,36 +3,87 @@ package util
 import (
 	"edna/internal/types"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
 var (
 	RequestTimeout = 2 * time.Second
 	ErrInvalidID   = errors.New("invalid id parameter")
+	ErrEmptyBody   = errors.New("Request body is required")
 )
 
-// / Escreve uma reposta com o corpo em JSON com o status passado
+// / Escreve uma reposta com o corpo em JSON com o status passado.
+// / O JSON é compacto, ou indentado se o ResponseWriter foi criado por WithPrettyJSON.
+// / Se o cliente negociou XML (WithXML) o corpo é escrito em XML; valores sem
+// / representação em XML (ex: mapas) respondem 406.
 func WriteJSON(w http.ResponseWriter, status int, v any) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	res, err := json.Marshal(v)
+	res, contentType, err := encodeBody(w, v)
 	if err != nil {
+		var xmlErr *xml.UnsupportedTypeError
+		if errors.As(err, &xmlErr) {
+			ErrorJSON(w, "Response cannot be represented as XML, use `Accept: application/json`", http.StatusNotAcceptable)
+			return nil
+		}
 		return err
 	}
 
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
 	if _, err = w.Write(res); err != nil {
 		return err
 	}
 	return nil
 }
 
-// / Lê o corpo (em json) da requisição, decodifica e armazena no destino
+// / Lê o corpo (em json) da requisição, decodifica e armazena no destino.
+// / Erros de decodificação são traduzidos em mensagens legíveis, indicando o
+// / campo ou a posição do problema.
+// / Campos desconhecidos são ignorados, a menos que o cliente peça `?strict=true`.
 func ReadJSON(r *http.Request, dst any) error {
-	return json.NewDecoder(r.Body).Decode(dst)
+	if r.Body == nil {
+		return ErrEmptyBody
+	}
+	dec := json.NewDecoder(r.Body)
+	if strict, _ := strconv.ParseBool(r.URL.Query().Get("strict")); strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(dst); err != nil {
+		return jsonDecodeError(err)
+	}
+	return nil
+}
+
+func jsonDecodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("Malformed JSON body at byte offset %d", syntaxErr.Offset)
+	case errors.Is(err, io.EOF):
+		return ErrEmptyBody
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("Malformed JSON body: unexpected end of input")
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return fmt.Errorf("Invalid JSON body: expected %s", typeErr.Type)
+		}
+		return fmt.Errorf("Invalid value for field `%s`: expected %s", typeErr.Field, typeErr.Type)
+	}
+
+	// O pacote encoding/json não exporta um tipo para campos desconhecidos
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return fmt.Errorf("Unknown field `%s` in request body", strings.Trim(field, `"`))
+	}
+	return err
 }
 
 func GetIDParam(r *http.Request) (int64, error) {
@@ -44,6 +95,12 @@ func GetIDParam(r *http.Request) (int64, error) {
 	return id, nil
 }
 
+// Indica se a requisição pediu apenas uma simulação (`?dry_run=true`) da operação destrutiva
+func IsDryRun(r *http.Request) bool {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	return dryRun
+}
+
 func GetComposedID(r *http.Request) (int64, int64, error) {
 	idStr1 := r.PathValue("id_produto")
 	idStr2 := r.PathValue("id_oferta")
@@ -64,16 +121,27 @@ func GetComposedID(r *http.Request) (int64, int64, error) {
 
 // / Escreve uma mensagem de error com o status passado, o corpo da mensagem será em JSON
 func ErrorJSON(w http.ResponseWriter, msg string, status int) {
-	w.Header().Add("X-Content-Type-Options", "nosniff")
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+	writeError(w, types.NewErrorResponse(status, msg), status)
+}
 
-	res, err := json.Marshal(types.NewErrorResponse(msg))
+// Igual a ErrorJSON, mas com `code` no campo `error` em vez do código derivado do status
+func ErrorCodeJSON(w http.ResponseWriter, code, msg string, status int) {
+	res := types.NewErrorResponse(status, msg)
+	res.Error = code
+	writeError(w, res, status)
+}
+
+func writeError(w http.ResponseWriter, body types.ErrorResponse, status int) {
+	res, contentType, err := encodeBody(w, body)
 	// Impossivel
 	if err != nil {
 		log.Printf("Error ao criar mensagem em json: %s", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
+
+	w.Header().Add("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
 	w.Write(res)
 }
@@ -0,0 +1,141 @@
+package util
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type jsonTestPayload struct {
+	Nome      string `json:"nome"`
+	IDProduto int64  `json:"id_produto"`
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		contains string
+	}{
+		{"empty body", ``, "Request body is required"},
+		{"blank body", "  \n", "Request body is required"},
+		{"truncated body", `{"nome": "Cerveja"`, "unexpected end of input"},
+		{"syntax error", `{"nome": "Cerveja",}`, "byte offset 20"},
+		{"type mismatch", `{"id_produto": "x"}`, "`id_produto`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			var dst jsonTestPayload
+			err := ReadJSON(r, &dst)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("expected error to contain %q; got %q", tt.contains, err.Error())
+			}
+		})
+	}
+}
+
+func TestReadJSONUnknownFields(t *testing.T) {
+	body := `{"nome": "Cerveja", "id": 3}`
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	var lenient jsonTestPayload
+	if err := ReadJSON(r, &lenient); err != nil {
+		t.Fatalf("expected unknown field to be ignored by default; got %v", err)
+	}
+	if lenient.Nome != "Cerveja" {
+		t.Errorf("expected nome to be decoded; got %q", lenient.Nome)
+	}
+
+	r = httptest.NewRequest("POST", "/?strict=true", strings.NewReader(body))
+	var strict jsonTestPayload
+	err := ReadJSON(r, &strict)
+	if err == nil {
+		t.Fatal("expected unknown field to be rejected in strict mode")
+	}
+	if err.Error() != "Unknown field `id` in request body" {
+		t.Errorf("unexpected error message: %q", err.Error())
+	}
+}
+
+func TestWriteJSONPretty(t *testing.T) {
+	payload := jsonTestPayload{Nome: "Cerveja", IDProduto: 3}
+
+	compact := httptest.NewRecorder()
+	if err := WriteJSON(compact, 200, payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := compact.Body.String(), `{"nome":"Cerveja","id_produto":3}`; got != want {
+		t.Errorf("expected compact JSON %s; got %s", want, got)
+	}
+
+	pretty := httptest.NewRecorder()
+	if err := WriteJSON(WithPrettyJSON(pretty), 200, payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "{\n  \"nome\": \"Cerveja\",\n  \"id_produto\": 3\n}"
+	if got := pretty.Body.String(); got != want {
+		t.Errorf("expected indented JSON %s; got %s", want, got)
+	}
+	if pretty.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected Content-Type application/json; got %q", pretty.Header().Get("Content-Type"))
+	}
+}
+
+func TestWriteJSONAsXML(t *testing.T) {
+	payload := []jsonTestPayload{{Nome: "Cerveja", IDProduto: 3}, {Nome: "Água", IDProduto: 4}}
+
+	rec := httptest.NewRecorder()
+	if err := WriteJSON(WithXML(rec), 200, payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != FormatXML {
+		t.Errorf("expected Content-Type %s; got %q", FormatXML, got)
+	}
+
+	var doc struct {
+		Items []jsonTestPayload `xml:"item"`
+	}
+	if err := xml.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("expected well-formed XML; got %v in %s", err, rec.Body.String())
+	}
+	if len(doc.Items) != 2 || doc.Items[1].Nome != "Água" || doc.Items[1].IDProduto != 4 {
+		t.Errorf("unexpected XML content: %+v", doc.Items)
+	}
+
+	// Mapas não têm representação em XML
+	rec = httptest.NewRecorder()
+	WriteJSON(WithXML(rec), 200, map[string]bool{"a": true})
+	if rec.Code != 406 {
+		t.Errorf("expected status 406 for a value without XML representation; got %d", rec.Code)
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		accept string
+		format string
+		ok     bool
+	}{
+		{"", FormatJSON, true},
+		{"*/*", FormatJSON, true},
+		{"application/json", FormatJSON, true},
+		{"application/xml", FormatXML, true},
+		{"text/xml", FormatXML, true},
+		{"application/json;q=0.5, application/xml", FormatXML, true},
+		{"application/xml;q=0.2, */*;q=0.8", FormatJSON, true},
+		{"text/csv", "", false},
+		{"application/xml;q=0", "", false},
+	}
+	for _, tt := range tests {
+		format, ok := NegotiateFormat(tt.accept)
+		if format != tt.format || ok != tt.ok {
+			t.Errorf("NegotiateFormat(%q) = %q, %v; expected %q, %v", tt.accept, format, ok, tt.format, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Retorna uma cópia do filtro com o limite de segurança `max` (DB.MaxUnboundedRows)
+// aplicado quando a requisição não informou `limit`.
+func (ff Filter) Bounded(max uint32) Filter {
+	if ff.Limit == 0 && max > 0 {
+		ff.Limit = max
+	}
+	return ff
+}
+
+// Escreve os headers de paginação `X-Total-Count` e `Link` (RFC 5988) de uma listagem que
+// retornou `returned` dos `total` registros. O header `Link` só é escrito quando a requisição
+// é paginada (possui `limit`) ou quando o resultado foi truncado por DB.MaxUnboundedRows
+// (retornou menos linhas que o restante), caso em que também é escrito um `Warning`.
+func SetPaginationHeaders(w http.ResponseWriter, r *http.Request, filter Filter, returned int, total int64) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	if filter.Limit == 0 {
+		if returned == 0 || total <= int64(filter.Offset)+int64(returned) {
+			return
+		}
+		filter.Limit = uint32(returned)
+		w.Header().Set("Warning", fmt.Sprintf(
+			`199 - "Result truncated to %d of %d rows, use limit and offset to paginate"`, filter.Limit, total))
+	}
+
+	// RequestURI mantém o caminho original, mesmo após http.StripPrefix
+	u, err := url.ParseRequestURI(r.RequestURI)
+	if err != nil {
+		u = r.URL
+	}
+
+	limit := int64(filter.Limit)
+	offset := int64(filter.Offset)
+	lastOffset := int64(0)
+	if total > 0 {
+		lastOffset = ((total - 1) / limit) * limit
+	}
+
+	links := []string{pageLink(u, 0, limit, "first")}
+	if offset > 0 {
+		links = append(links, pageLink(u, max(offset-limit, 0), limit, "prev"))
+	}
+	if offset+limit < total {
+		links = append(links, pageLink(u, offset+limit, limit, "next"))
+	}
+	links = append(links, pageLink(u, lastOffset, limit, "last"))
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+func pageLink(u *url.URL, offset, limit int64, rel string) string {
+	q := u.Query()
+	q.Set("offset", strconv.FormatInt(offset, 10))
+	q.Set("limit", strconv.FormatInt(limit, 10))
+
+	page := *u
+	page.RawQuery = q.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, page.String(), rel)
+}
@@ -0,0 +1,98 @@
+package util
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetPaginationHeadersMiddlePage(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/produtos?offset=10&limit=10&sort=nome", nil)
+	w := httptest.NewRecorder()
+
+	SetPaginationHeaders(w, r, Filter{Offset: 10, Limit: 10}, 10, 35)
+
+	if got := w.Header().Get("X-Total-Count"); got != "35" {
+		t.Errorf("expected X-Total-Count 35; got %q", got)
+	}
+
+	link := w.Header().Get("Link")
+	expected := []string{
+		`</v1/produtos?limit=10&offset=0&sort=nome>; rel="first"`,
+		`</v1/produtos?limit=10&offset=0&sort=nome>; rel="prev"`,
+		`</v1/produtos?limit=10&offset=20&sort=nome>; rel="next"`,
+		`</v1/produtos?limit=10&offset=30&sort=nome>; rel="last"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(link, e) {
+			t.Errorf("expected Link header to contain %s; got %s", e, link)
+		}
+	}
+}
+
+func TestSetPaginationHeadersWithoutLimit(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/produtos", nil)
+	w := httptest.NewRecorder()
+
+	SetPaginationHeaders(w, r, Filter{}, 35, 35)
+
+	if got := w.Header().Get("X-Total-Count"); got != "35" {
+		t.Errorf("expected X-Total-Count 35; got %q", got)
+	}
+	if got := w.Header().Get("Link"); got != "" {
+		t.Errorf("expected no Link header for unpaginated request; got %q", got)
+	}
+}
+
+func TestSetPaginationHeadersAtUnboundedCap(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/produtos", nil)
+	w := httptest.NewRecorder()
+
+	SetPaginationHeaders(w, r, Filter{}, 20, 20)
+
+	if got := w.Header().Get("Warning"); got != "" {
+		t.Errorf("expected no Warning header when total is at the cap; got %q", got)
+	}
+	if got := w.Header().Get("Link"); got != "" {
+		t.Errorf("expected no Link header when total is at the cap; got %q", got)
+	}
+}
+
+func TestSetPaginationHeadersAboveUnboundedCap(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/produtos", nil)
+	r.RequestURI = "/v1/produtos"
+	w := httptest.NewRecorder()
+
+	SetPaginationHeaders(w, r, Filter{}, 20, 21)
+
+	if got := w.Header().Get("X-Total-Count"); got != "21" {
+		t.Errorf("expected X-Total-Count 21; got %q", got)
+	}
+	warning := w.Header().Get("Warning")
+	if !strings.Contains(warning, "truncated to 20 of 21 rows") {
+		t.Errorf("expected truncation Warning header; got %q", warning)
+	}
+	link := w.Header().Get("Link")
+	if !strings.Contains(link, `offset=20`) || !strings.Contains(link, `rel="next"`) {
+		t.Errorf("expected Link header pointing to the next page; got %q", link)
+	}
+}
+
+func TestFilterBounded(t *testing.T) {
+	if got := (Filter{}).Bounded(20).Limit; got != 20 {
+		t.Errorf("expected unbounded filter to be capped at 20; got %d", got)
+	}
+	if got := (Filter{Limit: 50}).Bounded(20).Limit; got != 50 {
+		t.Errorf("expected explicit limit to be kept; got %d", got)
+	}
+
+	var values []any
+	filter := Filter{}.Bounded(20)
+	if query := filter.ToQuery(&values, "p"); !strings.Contains(query, "LIMIT") {
+		t.Errorf("expected capped query to have a LIMIT; got %q", query)
+	}
+
+	if got := (Filter{}).Bounded(0).Limit; got != 0 {
+		t.Errorf("expected cap to be disabled; got %d", got)
+	}
+}
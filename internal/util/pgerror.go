@@ -0,0 +1,58 @@
+package util
+
+import (
+	"edna/internal/types"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Códigos do PostgreSQL traduzidos para erros de domínio
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// Erro de domínio traduzido de um erro do PostgreSQL. O erro original continua
+// acessível por errors.As, mas não aparece na mensagem.
+type pgDomainError struct {
+	err   error
+	cause error
+}
+
+func (e *pgDomainError) Error() string {
+	return e.err.Error()
+}
+
+func (e *pgDomainError) Unwrap() []error {
+	return []error{e.err, e.cause}
+}
+
+// Traduz violações de unicidade e de chave estrangeira em types.ErrAlreadyExists,
+// types.ErrReferenceNotFound ou types.ErrStillReferenced, com o detalhe do banco
+// (ex: `Key (cnpj)=(123) already exists.`) na mensagem. Outros erros são retornados como estão.
+func mapPgError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var domain error
+	switch {
+	case pgErr.Code == pgUniqueViolation:
+		domain = types.ErrAlreadyExists
+	case pgErr.Code == pgForeignKeyViolation && strings.Contains(pgErr.Detail, "is still referenced"):
+		domain = types.ErrStillReferenced
+	case pgErr.Code == pgForeignKeyViolation:
+		domain = types.ErrReferenceNotFound
+	default:
+		return err
+	}
+
+	if pgErr.Detail != "" {
+		domain = fmt.Errorf("%w: %s", domain, pgErr.Detail)
+	}
+	return &pgDomainError{err: domain, cause: err}
+}
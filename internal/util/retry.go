@@ -0,0 +1,58 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"edna/internal/types"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Espera base entre as tentativas, dobrada a cada nova tentativa e somada a um valor aleatório
+var readRetryDelay = 20 * time.Millisecond
+
+// Códigos do PostgreSQL que indicam que a mesma consulta pode dar certo se repetida
+var transientCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// Indica se o erro é uma falha de serialização ou deadlock do PostgreSQL
+func IsTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && transientCodes[pgErr.Code]
+}
+
+// Executa a consulta de leitura fn repetindo-a até db.ReadRetries vezes enquanto ela falhar
+// com um erro transitório. Nunca use com escritas: repeti-las pode duplicar os efeitos.
+// Os stores envolvem seus métodos de leitura inteiros (consulta, iteração e Scan), pois
+// o erro pode aparecer em rows.Next ou rows.Err, depois que a consulta já começou.
+// Cada tentativa tem o resultado registrado em db.Breaker e, com o circuito aberto, retorna
+// types.ErrDatabaseUnavailable sem consultar o banco. Quem decide qual requisição testa o
+// circuito meio aberto é o middleware do servidor (ver CircuitBreaker.Allow).
+func RetryRead[T any](ctx context.Context, db *DB, fn func() (T, error)) (T, error) {
+	result, err := breakerRead(db.Breaker, fn)
+	for attempt := 0; attempt < db.ReadRetries && IsTransient(err); attempt++ {
+		delay := readRetryDelay<<attempt + rand.N(readRetryDelay)
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(delay):
+		}
+		result, err = breakerRead(db.Breaker, fn)
+	}
+	return result, err
+}
+
+func breakerRead[T any](cb *CircuitBreaker, fn func() (T, error)) (T, error) {
+	if cb.State() == BreakerOpen {
+		var zero T
+		return zero, types.ErrDatabaseUnavailable
+	}
+	result, err := fn()
+	cb.Record(err)
+	return result, err
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRetryReadTransient(t *testing.T) {
+	calls := 0
+	count, err := RetryRead(context.Background(), &DB{ReadRetries: 2}, func() (int64, error) {
+		calls++
+		if calls == 1 {
+			return 0, &pgconn.PgError{Code: "40001"}
+		}
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatalf("expected the retry to succeed; got %v", err)
+	}
+	if count != 42 || calls != 2 {
+		t.Errorf("expected 42 after 2 calls; got %d after %d", count, calls)
+	}
+}
+
+func TestRetryReadGivesUp(t *testing.T) {
+	calls := 0
+	_, err := RetryRead(context.Background(), &DB{ReadRetries: 2}, func() (int64, error) {
+		calls++
+		return 0, &pgconn.PgError{Code: "40P01"}
+	})
+	if !IsTransient(err) || calls != 3 {
+		t.Errorf("expected the deadlock after 3 calls; got %v after %d", err, calls)
+	}
+}
+
+func TestRetryReadPermanent(t *testing.T) {
+	calls := 0
+	_, err := RetryRead(context.Background(), &DB{ReadRetries: 2}, func() (int64, error) {
+		calls++
+		return 0, errors.New("syntax error")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("expected other errors not to be retried; got %v after %d calls", err, calls)
+	}
+}
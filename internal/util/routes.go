@@ -0,0 +1,72 @@
+package util
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Métodos de registro de rotas do http.ServeMux usados pelos handlers
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// Rota registrada, Method vazio indica que aceita qualquer método
+type Route struct {
+	Method string `json:"method" xml:"method"`
+	Path   string `json:"path" xml:"path"`
+}
+
+// Tabela com todas as rotas registradas através dos Mux retornados por Record
+type RouteTable struct {
+	routes []Route
+}
+
+// Retorna um Mux que registra as rotas em `mux` e as anota na tabela, com `prefix`
+// adicionado ao caminho (o prefixo removido por http.StripPrefix, por exemplo).
+func (t *RouteTable) Record(mux Mux, prefix string) Mux {
+	return &recordingMux{mux: mux, table: t, prefix: prefix}
+}
+
+// Rotas registradas, ordenadas por caminho e método
+func (t *RouteTable) Routes() []Route {
+	routes := make([]Route, len(t.routes))
+	copy(routes, t.routes)
+	sortRoutes(routes)
+	return routes
+}
+
+// Ordena as rotas por caminho e método
+func sortRoutes(routes []Route) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+}
+
+type recordingMux struct {
+	mux    Mux
+	table  *RouteTable
+	prefix string
+}
+
+func (m *recordingMux) Handle(pattern string, handler http.Handler) {
+	m.mux.Handle(pattern, handler)
+	m.add(pattern)
+}
+
+func (m *recordingMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.mux.HandleFunc(pattern, handler)
+	m.add(pattern)
+}
+
+func (m *recordingMux) add(pattern string) {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		method, path = "", pattern
+	}
+	m.table.routes = append(m.table.routes, Route{Method: method, Path: m.prefix + strings.TrimSpace(path)})
+}
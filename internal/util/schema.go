@@ -0,0 +1,139 @@
+package util
+
+import (
+	"encoding"
+	"reflect"
+	"time"
+)
+
+// Subconjunto do JSON Schema suficiente para descrever os corpos de requisição da API
+type JSONSchema struct {
+	Schema           string                 `json:"$schema,omitempty"`
+	Title            string                 `json:"title,omitempty"`
+	Type             any                    `json:"type,omitempty"`
+	Format           string                 `json:"format,omitempty"`
+	Properties       map[string]*JSONSchema `json:"properties,omitempty"`
+	Required         []string               `json:"required,omitempty"`
+	MinLength        *int                   `json:"minLength,omitempty"`
+	MaxLength        *int                   `json:"maxLength,omitempty"`
+	Minimum          *float64               `json:"minimum,omitempty"`
+	Maximum          *float64               `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64               `json:"exclusiveMinimum,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Tipos serializados como texto podem informar o `format` usado no schema (ex: "date")
+type schemaFormatter interface {
+	SchemaFormat() string
+}
+
+// Gera o JSON Schema de uma struct a partir das tags `json` e `validate`, as mesmas
+// usadas por Validate, para que clientes validem os formulários com as mesmas regras.
+func SchemaFor(title string, v any) *JSONSchema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	schema := &JSONSchema{
+		Schema:     "https://json-schema.org/draft/2020-12/schema",
+		Title:      title,
+		Type:       "object",
+		Properties: map[string]*JSONSchema{},
+	}
+	addProperties(schema, t)
+	return schema
+}
+
+func addProperties(schema *JSONSchema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addProperties(schema, field.Type)
+			continue
+		}
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		name := jsonFieldName(field)
+		prop := typeSchema(field.Type)
+		if tag, ok := field.Tag.Lookup("validate"); ok {
+			for _, rule := range parseValidateTag(tag) {
+				if rule.Name == "required" {
+					schema.Required = append(schema.Required, name)
+					if field.Type.Kind() == reflect.String {
+						prop.MinLength = ptr(1)
+					}
+				} else {
+					applyRule(prop, field.Type, rule)
+				}
+			}
+		}
+		schema.Properties[name] = prop
+	}
+}
+
+func typeSchema(t reflect.Type) *JSONSchema {
+	nullable := t.Kind() == reflect.Pointer
+	if nullable {
+		t = t.Elem()
+	}
+
+	prop := &JSONSchema{}
+	switch {
+	case t == timeType:
+		prop.Type, prop.Format = "string", "date-time"
+	case t.Implements(textMarshalerType):
+		prop.Type = "string"
+		if f, ok := reflect.Zero(t).Interface().(schemaFormatter); ok {
+			prop.Format = f.SchemaFormat()
+		}
+	case t.Kind() == reflect.String:
+		prop.Type = "string"
+	case t.Kind() == reflect.Bool:
+		prop.Type = "boolean"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		prop.Type = "integer"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		prop.Type = "number"
+	case t.Kind() == reflect.Slice:
+		prop.Type = "array"
+	default:
+		prop.Type = "object"
+	}
+
+	if nullable {
+		prop.Type = []string{prop.Type.(string), "null"}
+	}
+	return prop
+}
+
+func applyRule(prop *JSONSchema, t reflect.Type, rule validateRule) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.String {
+		if rule.Name == "max" {
+			prop.MaxLength = ptr(int(rule.Param))
+		}
+		return
+	}
+
+	switch rule.Name {
+	case "max":
+		prop.Maximum = ptr(rule.Param)
+	case "min":
+		prop.Minimum = ptr(rule.Param)
+	case "gt":
+		prop.ExclusiveMinimum = ptr(rule.Param)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Diferenças entre as rotas registradas e as documentadas no spec swagger
+type RouteDiff struct {
+	// Registradas, mas ausentes do spec
+	Undocumented []Route `json:"undocumented"`
+	// Presentes no spec, mas não registradas
+	Unimplemented []Route `json:"unimplemented"`
+}
+
+var (
+	pathParamRegex = regexp.MustCompile(`\{[^}]*\}`)
+	specMethods    = []string{"get", "head", "post", "put", "patch", "delete", "options"}
+)
+
+// Compara os caminhos de um spec swagger 2.0 (JSON) com as rotas registradas. `prefix` é removido
+// das rotas antes da comparação, já que os caminhos do spec são relativos ao `basePath`.
+// Parâmetros são comparados sem o nome (`{id}` casa com `{id_produto}`), rotas sem método
+// casam com qualquer método documentado e rotas GET também atendem HEAD.
+func DiffRoutesVsSpec(spec []byte, routes []Route, prefix string) (RouteDiff, error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return RouteDiff{}, err
+	}
+
+	documented := make(map[Route]bool)
+	documentedPaths := make(map[string]bool)
+	for path, operations := range doc.Paths {
+		for _, method := range specMethods {
+			if _, ok := operations[method]; ok {
+				documented[Route{Method: strings.ToUpper(method), Path: normalizeParams(path)}] = true
+				documentedPaths[normalizeParams(path)] = true
+			}
+		}
+	}
+
+	implemented := make(map[Route]bool)
+	implementedPaths := make(map[string]bool)
+	var diff RouteDiff
+	for _, route := range routes {
+		route.Path = strings.TrimPrefix(route.Path, prefix)
+		path := normalizeParams(route.Path)
+		implemented[Route{Method: route.Method, Path: path}] = true
+		if route.Method == "" {
+			implementedPaths[path] = true
+			if !documentedPaths[path] {
+				diff.Undocumented = append(diff.Undocumented, route)
+			}
+		} else if !documented[Route{Method: route.Method, Path: path}] {
+			diff.Undocumented = append(diff.Undocumented, route)
+		}
+	}
+
+	for path, operations := range doc.Paths {
+		normalized := normalizeParams(path)
+		for _, method := range specMethods {
+			if _, ok := operations[method]; !ok {
+				continue
+			}
+			method = strings.ToUpper(method)
+			if implementedPaths[normalized] || implemented[Route{Method: method, Path: normalized}] ||
+				(method == "HEAD" && implemented[Route{Method: "GET", Path: normalized}]) {
+				continue
+			}
+			diff.Unimplemented = append(diff.Unimplemented, Route{Method: method, Path: path})
+		}
+	}
+
+	sortRoutes(diff.Undocumented)
+	sortRoutes(diff.Unimplemented)
+	return diff, nil
+}
+
+func normalizeParams(path string) string {
+	return pathParamRegex.ReplaceAllString(path, "{}")
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRoutesVsSpec(t *testing.T) {
+	spec := []byte(`{
+		"basePath": "/api/v1",
+		"paths": {
+			"/health": {"get": {}},
+			"/produtos": {"get": {}, "post": {}},
+			"/produtos/{id}": {"get": {}, "head": {}, "delete": {}},
+			"/produtos/atrasados": {"get": {}}
+		}
+	}`)
+	routes := []Route{
+		{Method: "", Path: "/v1/health"},
+		{Method: "GET", Path: "/v1/produtos"},
+		{Method: "POST", Path: "/v1/produtos"},
+		{Method: "GET", Path: "/v1/produtos/{id_produto}"},
+		{Method: "DELETE", Path: "/v1/produtos/{id_produto}"},
+		{Method: "GET", Path: "/v1/produtos/pendentes"},
+	}
+
+	diff, err := DiffRoutesVsSpec(spec, routes, "/v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []Route{{Method: "GET", Path: "/produtos/pendentes"}}; !reflect.DeepEqual(diff.Undocumented, expected) {
+		t.Errorf("expected undocumented %v; got %v", expected, diff.Undocumented)
+	}
+	if expected := []Route{{Method: "GET", Path: "/produtos/atrasados"}}; !reflect.DeepEqual(diff.Unimplemented, expected) {
+		t.Errorf("expected unimplemented %v; got %v", expected, diff.Unimplemented)
+	}
+}
+
+func TestDiffRoutesVsSpecInvalid(t *testing.T) {
+	if _, err := DiffRoutesVsSpec([]byte("not json"), nil, ""); err == nil {
+		t.Error("expected an error for an invalid spec")
+	}
+}
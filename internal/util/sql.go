@@ -5,10 +5,61 @@ import (
 	"database/sql"
 )
 
+// Executa a query aplicando o filtro. Listagens sem `limit` são limitadas a db.MaxUnboundedRows.
+// Não repete a consulta: erros transitórios podem surgir durante a iteração das linhas, então
+// o método do store que consome as linhas é que deve ser envolvido por RetryRead.
+func QueryRowsWithFilter(db *DB, ctx context.Context, query string, filter *Filter, tableAlias string) (*sql.Rows, error) {
+	bounded := filter.Bounded(db.MaxUnboundedRows)
+	return queryWithFilter(db, ctx, query, &bounded, tableAlias)
+}
+
+// Igual a QueryRowsWithFilter, mas sem o limite db.MaxUnboundedRows. Use apenas quando as
+// linhas são consumidas uma a uma, sem acumular o resultado em memória (ex: exportações).
+// Apenas o início da consulta é repetido por RetryRead: linhas já entregues não podem ser
+// desfeitas, então erros durante a iteração são devolvidos ao chamador.
+func StreamRowsWithFilter(db *DB, ctx context.Context, query string, filter *Filter, tableAlias string) (*sql.Rows, error) {
+	return RetryRead(ctx, db, func() (*sql.Rows, error) {
+		return queryWithFilter(db, ctx, query, filter, tableAlias)
+	})
+}
 
-func QueryRowsWithFilter(db *sql.DB, ctx context.Context, query string, filter *Filter, tableAlias string) (*sql.Rows, error) {
+func queryWithFilter(db *DB, ctx context.Context, query string, filter *Filter, tableAlias string) (*sql.Rows, error) {
 	var filterValues []any
 	query += filter.ToQuery(&filterValues, tableAlias)
 	// fmt.Println(query)
 	return db.QueryContext(ctx, query, filterValues...)
 }
+
+// Conta as linhas de uma query `SELECT COUNT(*) FROM ...` aplicando apenas as condições do filtro,
+// ignorando ordenação e paginação.
+func CountRowsWithFilter(db *DB, ctx context.Context, query string, filter *Filter, tableAlias string) (int64, error) {
+	var filterValues []any
+	where, _ := filter.ToWhereQuery(&filterValues, tableAlias)
+	query += where
+
+	return RetryRead(ctx, db, func() (int64, error) {
+		var count int64
+		err := db.QueryRowContext(ctx, query, filterValues...).Scan(&count)
+		return count, err
+	})
+}
+
+// Executa uma query que retorna, em uma única linha, quantos registros de cada tabela seriam removidos
+// em cascata junto com o registro `id`. As colunas devem estar na mesma ordem de `tables`.
+// Retorna sql.ErrNoRows quando o registro não existe.
+func CountCascade(db *DB, ctx context.Context, query string, id int64, tables ...string) (map[string]int64, error) {
+	counts := make([]int64, len(tables))
+	dest := make([]any, len(tables))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	if err := db.QueryRowContext(ctx, query, id).Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	cascata := make(map[string]int64, len(tables))
+	for i, table := range tables {
+		cascata[table] = counts[i]
+	}
+	return cascata, nil
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"edna/internal/types"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Classifica um erro vindo dos stores em um *types.StoreError. Erros do PostgreSQL com
+// tradução de domínio (mapPgError) são substituídos por ela. Erros já classificados
+// são retornados como estão e nil continua nil.
+func ClassifyStoreError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var storeErr *types.StoreError
+	if errors.As(err, &storeErr) {
+		return err
+	}
+	err = mapPgError(err)
+	return &types.StoreError{Kind: storeErrorKind(err), Err: err}
+}
+
+func storeErrorKind(err error) types.ErrorKind {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, types.ErrNotFound) {
+		return types.KindNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		// Classe 23: violação de restrição de integridade (unique, foreign key, check...)
+		case strings.HasPrefix(pgErr.Code, "23"):
+			return types.KindConflict
+		// Classe 08: exceção de conexão, 57P: o servidor está desligando
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "57P"):
+			return types.KindConnection
+		}
+		return types.KindUnknown
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.Is(err, types.ErrDatabaseUnavailable) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, driver.ErrBadConn) || errors.As(err, &connectErr) || errors.As(err, &netErr) {
+		return types.KindConnection
+	}
+	return types.KindUnknown
+}
+
+// Código das respostas 503 causadas por falha de conexão com o banco ou circuit breaker aberto
+const DatabaseUnavailable = "database_unavailable"
+
+// Escreve a resposta de erro de uma operação dos stores com o status da categoria do erro:
+// 404 para registros inexistentes, 409 para conflitos e 503 para falhas de conexão.
+// Erros sem categoria respondem com `fallback`.
+func StoreErrorJSON(w http.ResponseWriter, err error, fallback int) {
+	var storeErr *types.StoreError
+	if !errors.As(ClassifyStoreError(err), &storeErr) {
+		ErrorJSON(w, err.Error(), fallback)
+		return
+	}
+
+	switch storeErr.Kind {
+	case types.KindNotFound:
+		ErrorJSON(w, "Not found.", http.StatusNotFound)
+	case types.KindConflict:
+		ErrorJSON(w, storeErr.Error(), http.StatusConflict)
+	case types.KindConnection:
+		ErrorCodeJSON(w, DatabaseUnavailable, "Database unavailable, please try again later.", http.StatusServiceUnavailable)
+	default:
+		ErrorJSON(w, storeErr.Error(), fallback)
+	}
+}
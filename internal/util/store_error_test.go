@@ -0,0 +1,100 @@
+package util
+
+import (
+	"database/sql"
+	"edna/internal/types"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestClassifyStoreError(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind types.ErrorKind
+	}{
+		{&pgconn.PgError{Code: "23505", ConstraintName: "fornecedor_cnpj_key"}, types.KindConflict},
+		{fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23503"}), types.KindConflict},
+		{sql.ErrNoRows, types.KindNotFound},
+		{types.ErrNotFound, types.KindNotFound},
+		{sql.ErrConnDone, types.KindConnection},
+		{&pgconn.PgError{Code: "08006"}, types.KindConnection},
+		{&pgconn.PgError{Code: "42601"}, types.KindUnknown},
+		{errors.New("boom"), types.KindUnknown},
+	}
+	for _, tt := range tests {
+		var storeErr *types.StoreError
+		if !errors.As(ClassifyStoreError(tt.err), &storeErr) {
+			t.Fatalf("expected a StoreError for %v", tt.err)
+		}
+		if storeErr.Kind != tt.kind {
+			t.Errorf("expected %v to be %s; got %s", tt.err, tt.kind, storeErr.Kind)
+		}
+		if !errors.Is(storeErr, tt.err) {
+			t.Errorf("expected the StoreError to wrap %v", tt.err)
+		}
+	}
+
+	if ClassifyStoreError(nil) != nil {
+		t.Error("expected nil to stay nil")
+	}
+}
+
+func TestStoreErrorJSON(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{&pgconn.PgError{Code: "23505"}, http.StatusConflict},
+		{sql.ErrNoRows, http.StatusNotFound},
+		{sql.ErrConnDone, http.StatusServiceUnavailable},
+		{errors.New("boom"), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		StoreErrorJSON(rec, tt.err, http.StatusUnprocessableEntity)
+		if rec.Code != tt.status {
+			t.Errorf("expected status %d for %v; got %d", tt.status, tt.err, rec.Code)
+		}
+	}
+}
+
+func TestMapPgError(t *testing.T) {
+	tests := []struct {
+		err    error
+		domain error
+	}{
+		{&pgconn.PgError{Code: "23505", Detail: "Key (cnpj)=(12345678000190) already exists."}, types.ErrAlreadyExists},
+		{&pgconn.PgError{Code: "23503", Detail: `Key (id_produto)=(99) is not present in table "produto".`}, types.ErrReferenceNotFound},
+		{&pgconn.PgError{Code: "23503", Detail: `Key (id_produto)=(1) is still referenced from table "lote".`}, types.ErrStillReferenced},
+	}
+	for _, tt := range tests {
+		err := ClassifyStoreError(tt.err)
+		if !errors.Is(err, tt.domain) {
+			t.Errorf("expected %v to map to %v; got %v", tt.err, tt.domain, err)
+		}
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) {
+			t.Errorf("expected the driver error to stay reachable from %v", err)
+		}
+		if msg := err.Error(); !strings.Contains(msg, tt.err.(*pgconn.PgError).Detail) || strings.Contains(msg, "SQLSTATE") {
+			t.Errorf("expected the message to carry only the detail; got %q", msg)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	StoreErrorJSON(rec, &pgconn.PgError{Code: "23505", Detail: "Key (cnpj)=(123) already exists."}, http.StatusUnprocessableEntity)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "Already exists: Key (cnpj)=(123) already exists.") {
+		t.Errorf("expected a 409 with the mapped message; got %d %s", rec.Code, rec.Body.String())
+	}
+
+	other := &pgconn.PgError{Code: "42601"}
+	if mapPgError(other) != error(other) {
+		t.Error("expected codes without translation to be returned as they are")
+	}
+}
@@ -0,0 +1,197 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Erro de validação de um único campo, identificado pelo nome usado no JSON.
+// Regras que envolvem mais de um campo são reportadas sem Field.
+type FieldError struct {
+	Field   string `json:"field,omitempty" xml:"field,omitempty"`
+	Message string `json:"message" xml:"message"`
+}
+
+func (fe FieldError) Error() string {
+	if fe.Field == "" {
+		return fe.Message
+	}
+	return fmt.Sprintf("Field `%s` %s", fe.Field, fe.Message)
+}
+
+// Todos os erros de validação encontrados em uma struct
+type ValidationErrors []FieldError
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, fe := range ve {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Resultado de uma validação feita sem persistir nada
+type ValidationResponse struct {
+	Valid  bool             `json:"valid" xml:"valid"`
+	Errors ValidationErrors `json:"errors,omitempty" xml:"errors,omitempty"`
+}
+
+// Valida os campos de uma struct a partir da tag `validate`, por exemplo:
+//
+//	Nome string `json:"nome" validate:"required,max=50"`
+//
+// Regras suportadas:
+//   - required: o campo não pode ter o valor zero (texto em branco, 0 ou ponteiro nulo)
+//   - max=N: textos com no máximo N caracteres, números menores ou iguais a N
+//   - min=N: números maiores ou iguais a N
+//   - gt=N: números estritamente maiores que N
+//
+// Ponteiros nulos só são verificados pela regra required. Structs embutidas são
+// validadas como se seus campos pertencessem à struct externa.
+// Regras de negócio que dependem de mais de um campo ficam nos métodos Validate dos modelos.
+func Validate(v any) error {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs ValidationErrors
+	validateStruct(val, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateStruct(val reflect.Value, errs *ValidationErrors) {
+	t := val.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			validateStruct(val.Field(i), errs)
+			continue
+		}
+		tag, ok := field.Tag.Lookup("validate")
+		if !ok || !field.IsExported() {
+			continue
+		}
+		if msg := validateField(val.Field(i), tag); msg != "" {
+			*errs = append(*errs, FieldError{Field: jsonFieldName(field), Message: msg})
+		}
+	}
+}
+
+// Retorna a mensagem do primeiro problema encontrado no campo ou "" se ele for válido
+func validateField(val reflect.Value, tag string) string {
+	for _, rule := range parseValidateTag(tag) {
+		if rule.Name == "required" {
+			if isBlank(val) {
+				return "is required"
+			}
+			continue
+		}
+
+		v := reflect.Indirect(val)
+		if !v.IsValid() {
+			continue
+		}
+		if msg := checkRule(v, rule); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// Regra de validação já interpretada, `max=50` vira {Name: "max", Param: 50}
+type validateRule struct {
+	Name  string
+	Param float64
+}
+
+// Interpreta o conteúdo da tag `validate`. Regras desconhecidas são um erro de
+// programação e por isso causam pânico.
+func parseValidateTag(tag string) []validateRule {
+	var rules []validateRule
+	for _, part := range strings.Split(tag, ",") {
+		name, param, hasParam := strings.Cut(strings.TrimSpace(part), "=")
+		rule := validateRule{Name: name}
+		switch name {
+		case "required":
+		case "max", "min", "gt":
+			n, err := strconv.ParseFloat(param, 64)
+			if !hasParam || err != nil {
+				panic(fmt.Sprintf("validate: rule %q requires a numeric parameter", name))
+			}
+			rule.Param = n
+		default:
+			panic(fmt.Sprintf("validate: unknown rule %q", name))
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func checkRule(v reflect.Value, rule validateRule) string {
+	param := strconv.FormatFloat(rule.Param, 'f', -1, 64)
+
+	if v.Kind() == reflect.String {
+		if rule.Name == "max" && float64(utf8.RuneCountInString(v.String())) > rule.Param {
+			return fmt.Sprintf("must have at most %s characters", param)
+		}
+		return ""
+	}
+
+	n, ok := number(v)
+	if !ok {
+		return ""
+	}
+	switch rule.Name {
+	case "max":
+		if n > rule.Param {
+			return fmt.Sprintf("must be at most %s", param)
+		}
+	case "min":
+		if n < rule.Param {
+			return fmt.Sprintf("must be at least %s", param)
+		}
+	case "gt":
+		if n <= rule.Param {
+			return fmt.Sprintf("must be greater than %s", param)
+		}
+	}
+	return ""
+}
+
+func isBlank(val reflect.Value) bool {
+	if val.Kind() == reflect.Pointer {
+		return val.IsNil()
+	}
+	if val.Kind() == reflect.String {
+		return strings.TrimSpace(val.String()) == ""
+	}
+	return val.IsZero()
+}
+
+func number(v reflect.Value) (float64, bool) {
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// Nome do campo no JSON, ou o nome do campo Go quando não há tag `json`
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"errors"
+	"testing"
+)
+
+type validateBase struct {
+	Nome string `json:"nome" validate:"required,max=5"`
+}
+
+type validatePayload struct {
+	validateBase
+	Preco      float64 `json:"preco" validate:"gt=0"`
+	Estoque    *int    `json:"estoque" validate:"min=0"`
+	Percentual int     `json:"percentual" validate:"min=0,max=100"`
+	Cpf        *string `json:"cpf" validate:"required"`
+	Livre      string  `json:"livre"`
+}
+
+func TestValidate(t *testing.T) {
+	cpf := "123"
+	negativo := -1
+
+	tests := []struct {
+		name    string
+		payload validatePayload
+		errs    map[string]string
+	}{
+		{
+			name:    "valid",
+			payload: validatePayload{validateBase: validateBase{Nome: "Ana"}, Preco: 1, Cpf: &cpf},
+		},
+		{
+			name:    "required",
+			payload: validatePayload{validateBase: validateBase{Nome: "   "}, Preco: 1},
+			errs:    map[string]string{"nome": "is required", "cpf": "is required"},
+		},
+		{
+			name:    "max length counts characters",
+			payload: validatePayload{validateBase: validateBase{Nome: "Joãozinho"}, Preco: 1, Cpf: &cpf},
+			errs:    map[string]string{"nome": "must have at most 5 characters"},
+		},
+		{
+			name:    "positivity",
+			payload: validatePayload{validateBase: validateBase{Nome: "Ana"}, Preco: 0, Estoque: &negativo, Percentual: 150, Cpf: &cpf},
+			errs: map[string]string{
+				"preco":      "must be greater than 0",
+				"estoque":    "must be at least 0",
+				"percentual": "must be at most 100",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.payload)
+			if len(tt.errs) == 0 {
+				if err != nil {
+					t.Fatalf("expected payload to be valid; got %v", err)
+				}
+				return
+			}
+
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("expected ValidationErrors; got %v", err)
+			}
+			if len(verrs) != len(tt.errs) {
+				t.Errorf("expected %d errors; got %v", len(tt.errs), verrs)
+			}
+			for _, fe := range verrs {
+				if tt.errs[fe.Field] != fe.Message {
+					t.Errorf("field %s: expected %q; got %q", fe.Field, tt.errs[fe.Field], fe.Message)
+				}
+			}
+		})
+	}
+}